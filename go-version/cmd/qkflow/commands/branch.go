@@ -0,0 +1,256 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneDryRun     bool
+	pruneYes        bool
+	pruneForce      bool
+	pruneMergedOnly bool
+)
+
+var branchCmd = &cobra.Command{
+	Use:   "branch",
+	Short: "Manage local and remote branches",
+	Long: `Work with the branches of the current repository.
+
+Available commands:
+  prune  - Delete branches whose PRs are merged or closed`,
+}
+
+var branchPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete local and remote branches whose PRs are merged or closed",
+	Long: `Look up the PR of every local branch on GitHub and delete the branches whose
+PR was merged or closed, locally and on the remote. Unlike 'git branch --merged'
+this also finds branches merged with squash or rebase.
+
+A branch is kept when:
+  - it has an open PR, or no PR at all
+  - it is the default branch, checked out, or the base of an open PR
+  - it has commits that are not in its PR (delete it anyway with --force)
+The remote branch is only deleted when it still points to the PR's last commit.
+
+Examples:
+  qkflow branch prune --dry-run     # show what would be deleted
+  qkflow branch prune --merged-only # keep branches of closed, unmerged PRs
+  qkflow branch prune -y            # delete without asking`,
+	Run: runBranchPrune,
+}
+
+func init() {
+	branchPruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Show what would be deleted without deleting")
+	branchPruneCmd.Flags().BoolVarP(&pruneYes, "yes", "y", false, "Skip the confirmation prompt")
+	branchPruneCmd.Flags().BoolVarP(&pruneForce, "force", "f", false, "Also delete local branches with commits that are not in their PR")
+	branchPruneCmd.Flags().BoolVar(&pruneMergedOnly, "merged-only", false, "Only delete branches of merged PRs, keep closed ones")
+
+	branchCmd.AddCommand(branchPruneCmd)
+}
+
+// prunableBranch is a local branch whose PR is merged or closed
+type prunableBranch struct {
+	git.LocalBranch
+	pr           *github.PullRequest
+	remoteBranch string // 要删除的远程分支，为空时保留远程分支
+}
+
+func runBranchPrune(cmd *cobra.Command, args []string) {
+	if !git.IsGitRepository() {
+		ui.Error("Not a git repository")
+		return
+	}
+
+	rc, err := github.DetectRepoContext()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to get repository info: %v", err))
+		return
+	}
+
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	ui.Info(fmt.Sprintf("Fetching %s...", rc.HeadRemote))
+	if err := git.Fetch(rc.HeadRemote); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to fetch: %v", err))
+	}
+
+	candidates := findPrunableBranches(ghClient, rc)
+	if candidates == nil {
+		return
+	}
+	if len(candidates) == 0 {
+		ui.Success("No branches to prune")
+		return
+	}
+
+	fmt.Println()
+	ui.Info("Branches of merged or closed PRs:")
+	for _, b := range candidates {
+		state := "closed"
+		if b.pr.Merged {
+			state = "merged"
+		}
+		where := "local"
+		if b.remoteBranch != "" {
+			where = fmt.Sprintf("local + %s/%s", rc.HeadRemote, b.remoteBranch)
+		}
+		fmt.Printf("  %s  #%d %s (%s, %s)\n", b.Name, b.pr.Number, b.pr.Title, state, where)
+	}
+	fmt.Println()
+
+	if pruneDryRun {
+		ui.Info(fmt.Sprintf("Dry run: %d branch(es) would be deleted", len(candidates)))
+		return
+	}
+
+	if !pruneYes {
+		ok, err := ui.PromptConfirm(fmt.Sprintf("Delete %d branch(es)?", len(candidates)), true)
+		if err != nil || !ok {
+			ui.Info("Prune cancelled")
+			return
+		}
+	}
+
+	deleted := 0
+	for _, b := range candidates {
+		if err := git.DeleteBranch(b.Name); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to delete local branch: %v", err))
+			continue
+		}
+		deleted++
+		ui.Success(fmt.Sprintf("Deleted local branch %s", b.Name))
+
+		if b.remoteBranch != "" {
+			if err := git.DeleteRemoteBranchFrom(rc.HeadRemote, b.remoteBranch); err != nil {
+				ui.Warning(fmt.Sprintf("Failed to delete remote branch: %v", err))
+			} else {
+				ui.Success(fmt.Sprintf("Deleted remote branch %s/%s", rc.HeadRemote, b.remoteBranch))
+			}
+		}
+	}
+
+	ui.Success(fmt.Sprintf("✅ Pruned %d branch(es)", deleted))
+}
+
+// findPrunableBranches looks up the PR of every local branch and returns the
+// branches that can be deleted, or nil on error
+func findPrunableBranches(ghClient *github.Client, rc *github.RepoContext) []prunableBranch {
+	branches, err := git.ListLocalBranches()
+	if err != nil {
+		ui.Error(err.Error())
+		return nil
+	}
+
+	// 其他 PR 的 base 分支删除后 PR 会被关闭
+	openPRs, err := ghClient.ListAllPullRequests(rc.BaseOwner, rc.BaseRepo, "open")
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to list open PRs: %v", err))
+		return nil
+	}
+	bases := make(map[string]int)
+	for _, pr := range openPRs {
+		bases[pr.Base] = pr.Number
+	}
+
+	// 在其他 worktree 中检出的分支不能删除
+	checkedOut := make(map[string]bool)
+	if worktrees, err := git.ListWorktrees(); err == nil {
+		for _, wt := range worktrees {
+			checkedOut[wt.Branch] = true
+		}
+	}
+	current, _ := git.GetCurrentBranch()
+	defaultBranch, _ := git.GetDefaultBranchOf(rc.BaseRemote)
+
+	ui.Info(fmt.Sprintf("Checking the PRs of %d local branch(es)...", len(branches)))
+	candidates := make([]prunableBranch, 0)
+	for _, b := range branches {
+		if b.Name == defaultBranch {
+			continue
+		}
+
+		// PR 的 head 是分支跟踪的远程分支，只处理推送到自己仓库的分支
+		head := b.Name
+		if b.UpstreamRemote != "" {
+			if b.UpstreamRemote != rc.HeadRemote {
+				continue
+			}
+			head = b.UpstreamBranch
+		}
+
+		prs, err := ghClient.FindPRsByBranch(rc.BaseOwner, rc.BaseRepo, rc.HeadRef(head), "all")
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Failed to find the PR of %s: %v", b.Name, err))
+			continue
+		}
+		pr := prunablePR(prs)
+		if pr == nil || (pruneMergedOnly && !pr.Merged) {
+			continue
+		}
+
+		switch {
+		case b.Name == current || checkedOut[b.Name]:
+			ui.Warning(fmt.Sprintf("Keeping %s: it is checked out (PR #%d is %s)", b.Name, pr.Number, pr.State))
+			continue
+		case bases[head] != 0 || bases[b.Name] != 0:
+			ui.Warning(fmt.Sprintf("Keeping %s: it is the base of an open PR", b.Name))
+			continue
+		case !pruneForce && !containedInPR(rc, b.SHA, pr):
+			ui.Warning(fmt.Sprintf("Keeping %s: it has commits that are not in PR #%d (use --force to delete)", b.Name, pr.Number))
+			continue
+		}
+
+		candidate := prunableBranch{LocalBranch: b, pr: pr}
+		// 远程分支有 PR 之后推送的提交时保留
+		if remoteSHA, err := git.RevParse(rc.HeadRemote + "/" + head); err == nil {
+			if containedInPR(rc, remoteSHA, pr) {
+				candidate.remoteBranch = head
+			} else {
+				ui.Warning(fmt.Sprintf("Keeping %s/%s: it has commits that are not in PR #%d", rc.HeadRemote, head, pr.Number))
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates
+}
+
+// prunablePR returns the most recent PR of a branch when none is open
+func prunablePR(prs []github.PullRequest) *github.PullRequest {
+	for i := range prs {
+		if prs[i].State == "open" {
+			return nil
+		}
+	}
+	if len(prs) == 0 {
+		return nil
+	}
+	// GitHub 按创建时间倒序返回
+	return &prs[0]
+}
+
+// containedInPR reports whether commit is the PR's last commit or one of its ancestors
+func containedInPR(rc *github.RepoContext, commit string, pr *github.PullRequest) bool {
+	if pr.HeadSHA == "" {
+		return false
+	}
+	if commit == pr.HeadSHA || git.IsAncestor(commit, pr.HeadSHA) {
+		return true
+	}
+
+	// PR 的提交可能不在本地，从 refs/pull/<number>/head 拉取后再比较
+	if err := git.FetchPullRequest(rc.BaseRemote, pr.Number); err != nil {
+		return false
+	}
+	return git.IsAncestor(commit, pr.HeadSHA)
+}
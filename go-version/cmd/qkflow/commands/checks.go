@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Wangggym/quick-workflow/internal/checks"
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/Wangggym/quick-workflow/pkg/config"
+)
+
+// runRepoChecks runs the checks of the repository's .qkflow.yaml whose paths
+// match the files HEAD changes over base, on the content of HEAD: local
+// changes are stashed while they run. The checks only run once the user
+// trusted them. All checks run; it returns false when any of them failed, or
+// weren't trusted, and the push must stop.
+func runRepoChecks(base string, skip bool) bool {
+	repo, err := loadRepoConfig()
+	if err != nil {
+		ui.Error(err.Error())
+		return false
+	}
+	if len(repo.Checks) == 0 {
+		return true
+	}
+	if skip {
+		ui.Warning(fmt.Sprintf("Skipping the checks of %s (--no-checks)", config.RepoConfigFile))
+		return true
+	}
+
+	sizes, err := git.BranchFiles(base)
+	if err != nil {
+		ui.Error(err.Error())
+		return false
+	}
+	files := make([]string, 0, len(sizes))
+	for file := range sizes {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	top, err := git.TopLevel()
+	if err != nil {
+		ui.Error(err.Error())
+		return false
+	}
+	if !confirmChecksTrusted(top, repo.Checks) {
+		return false
+	}
+
+	// 检查推送的提交，而不是工作区中未提交的改动
+	stashed, err := git.StashPush("qkflow: local changes during the checks")
+	if err != nil {
+		ui.Error(err.Error())
+		return false
+	}
+	if stashed {
+		ui.Info("Local changes are stashed while the checks run ('git stash pop' restores them if qkflow is interrupted)")
+		defer func() {
+			if err := git.StashPop(); err != nil {
+				ui.Warning(err.Error())
+				ui.Info("Your local changes are kept in the stash, restore them with 'git stash pop --index'")
+			}
+		}()
+	}
+
+	failed := make([]string, 0)
+	for i, check := range repo.Checks {
+		name := check.Name
+		if name == "" {
+			name = check.Run
+		}
+		progress := fmt.Sprintf("[%d/%d] %s", i+1, len(repo.Checks), name)
+
+		if check.Run == "" {
+			ui.Warning(fmt.Sprintf("%s: no 'run' command, skipped", progress))
+			continue
+		}
+		matched := checks.MatchingFiles(check, files)
+		if len(matched) == 0 {
+			ui.Info(fmt.Sprintf("%s: no matching changes, skipped", progress))
+			continue
+		}
+
+		ui.Info(fmt.Sprintf("%s: running...", progress))
+		result := checks.Run(top, checks.Command(check, matched))
+		if result.Err != nil {
+			ui.Error(fmt.Sprintf("%s: failed (%s)", progress, result.Duration.Round(100*time.Millisecond)))
+			if output := strings.TrimSpace(result.Output); output != "" {
+				fmt.Println(output)
+			} else {
+				fmt.Println(result.Err)
+			}
+			failed = append(failed, name)
+			continue
+		}
+		ui.Success(fmt.Sprintf("%s: passed (%s)", progress, result.Duration.Round(100*time.Millisecond)))
+	}
+
+	if len(failed) > 0 {
+		ui.Error(fmt.Sprintf("Check(s) failed: %s. Fix them before pushing, or skip the checks with --no-checks", strings.Join(failed, ", ")))
+		return false
+	}
+	return true
+}
+
+// confirmChecksTrusted asks the user to allow the checks of the repository at
+// top before they run for the first time, and whenever they change. It
+// returns false when they must not run.
+func confirmChecksTrusted(top string, repoChecks []config.Check) bool {
+	store, err := checks.NewTrustStore()
+	if err != nil {
+		ui.Error(err.Error())
+		return false
+	}
+	if store.Trusted(top, repoChecks) {
+		return true
+	}
+
+	ui.Warning(fmt.Sprintf("%s declares checks that run these commands before pushing:", config.RepoConfigFile))
+	for _, check := range repoChecks {
+		fmt.Printf("  %s\n", check.Run)
+	}
+	ok, err := ui.PromptConfirm("Do you trust them and want to run them?", false)
+	if err != nil {
+		if err.Error() == "interrupt" {
+			os.Exit(0)
+		}
+		ok = false
+	}
+	if !ok {
+		ui.Error(fmt.Sprintf("The checks of %s were not run. Review them, or push without them with --no-checks", config.RepoConfigFile))
+		return false
+	}
+
+	if err := store.Trust(top, repoChecks); err != nil {
+		ui.Warning(err.Error())
+	}
+	return true
+}
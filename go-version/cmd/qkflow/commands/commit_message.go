@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/Wangggym/quick-workflow/pkg/config"
+)
+
+// buildCommitMessage builds the commit message of pr create with
+// commit_message from the config, overridden by 'commit' in the repository's
+// .qkflow.yaml. Conventional Commits subjects are validated against the
+// allowed types and scopes.
+func buildCommitMessage(jiraTicket, prType, title string, interactive bool) (string, error) {
+	repo, err := loadRepoConfig()
+	if err != nil {
+		return "", err
+	}
+	convention := config.CommitConventionFor(repo)
+
+	data := git.CommitMessageData{
+		Ticket:  jiraTicket,
+		Type:    strings.ToLower(prType),
+		Scope:   prScope,
+		Title:   title,
+		Summary: stripTypePrefix(title, prType),
+	}
+
+	tmpl := convention.Template
+	if convention.Conventional {
+		if tmpl == "" {
+			tmpl = git.ConventionalCommitTemplate
+		}
+		if data.Type == "" {
+			if !interactive {
+				return "", fmt.Errorf("conventional commits need a type, pass it with --types")
+			}
+			data.Type, err = selectCommitType(convention.Types)
+			if err != nil {
+				return "", err
+			}
+		}
+		if data.Scope == "" && len(convention.Scopes) > 0 && interactive {
+			data.Scope, err = selectCommitScope(convention.Scopes)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	subject, err := git.RenderCommitSubject(tmpl, data)
+	if err != nil {
+		return "", err
+	}
+	if convention.Conventional {
+		if err := git.ValidateConventional(subject, convention.Types, convention.Scopes); err != nil {
+			return "", err
+		}
+	}
+
+	var body string
+	if convention.JiraLink && jiraTicket != "" {
+		if cfg := config.Get(); cfg != nil && cfg.JiraServiceAddress != "" {
+			body = fmt.Sprintf("Jira: %s/browse/%s", strings.TrimRight(cfg.JiraServiceAddress, "/"), jiraTicket)
+		}
+	}
+
+	trailers := make([]string, 0)
+	for _, coAuthor := range append(convention.CoAuthors, prCoAuthors...) {
+		if !strings.Contains(coAuthor, "<") || !strings.HasSuffix(coAuthor, ">") {
+			return "", fmt.Errorf("co-author %q must look like \"Name <email>\"", coAuthor)
+		}
+		trailers = append(trailers, "Co-authored-by: "+coAuthor)
+	}
+
+	message := git.BuildCommitMessage(subject, body, trailers)
+	ui.Info(fmt.Sprintf("Commit message: %s", subject))
+	return message, nil
+}
+
+// stripTypePrefix removes a leading "Type: " matching prType from a title
+func stripTypePrefix(title, prType string) string {
+	if prType == "" {
+		return title
+	}
+	if prefix, rest, ok := strings.Cut(title, ":"); ok && strings.EqualFold(strings.TrimSpace(prefix), prType) {
+		return strings.TrimSpace(rest)
+	}
+	return title
+}
+
+// selectCommitType asks for the Conventional Commits type
+func selectCommitType(types []string) (string, error) {
+	if len(types) == 0 {
+		types = git.DefaultConventionalTypes
+	}
+
+	commitType, err := ui.PromptSelect("Select the commit type:", types)
+	if err != nil {
+		if err.Error() == "interrupt" {
+			ui.Warning("Operation cancelled by user")
+			os.Exit(0)
+		}
+		return "", fmt.Errorf("failed to select commit type: %w", err)
+	}
+	return commitType, nil
+}
+
+// selectCommitScope asks for the Conventional Commits scope, which may be left out
+func selectCommitScope(scopes []string) (string, error) {
+	const noScope = "(no scope)"
+
+	scope, err := ui.PromptSelect("Select the commit scope:", append([]string{noScope}, scopes...))
+	if err != nil {
+		if err.Error() == "interrupt" {
+			ui.Warning("Operation cancelled by user")
+			os.Exit(0)
+		}
+		return "", fmt.Errorf("failed to select commit scope: %w", err)
+	}
+	if scope == noScope {
+		return "", nil
+	}
+	return scope, nil
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+)
+
+// resolveRepoContext detects the fork layout of the current repository. When
+// there is no fork yet and the user cannot push to origin (or forceFork is set),
+// it offers to fork the repository and adds the fork as the "fork" remote.
+func resolveRepoContext(forceFork bool) (*github.RepoContext, error) {
+	rc, err := github.DetectRepoContext()
+	if err != nil {
+		return nil, err
+	}
+	if rc.IsFork() {
+		ui.Info(fmt.Sprintf("Fork mode: pushing to %s/%s, PR against %s/%s", rc.HeadOwner, rc.HeadRepo, rc.BaseOwner, rc.BaseRepo))
+		return rc, nil
+	}
+
+	ghClient, err := github.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	if !forceFork {
+		canPush, err := ghClient.CanPush(rc.BaseOwner, rc.BaseRepo)
+		if err != nil || canPush {
+			// 无法判断权限时按原流程直接推送
+			return rc, nil
+		}
+
+		ui.Warning(fmt.Sprintf("You don't have push access to %s/%s", rc.BaseOwner, rc.BaseRepo))
+		ok, err := ui.PromptConfirm("Fork the repository and push your branch there?", true)
+		if err != nil {
+			if err.Error() == "interrupt" {
+				ui.Warning("Operation cancelled by user")
+				os.Exit(0)
+			}
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("no push access to %s/%s", rc.BaseOwner, rc.BaseRepo)
+		}
+	}
+
+	ui.Info(fmt.Sprintf("Forking %s/%s...", rc.BaseOwner, rc.BaseRepo))
+	fork, err := ghClient.CreateFork(rc.BaseOwner, rc.BaseRepo)
+	if err != nil {
+		return nil, err
+	}
+	ui.Success(fmt.Sprintf("Fork ready: %s/%s", fork.Owner, fork.Name))
+
+	// 与 origin 使用相同的协议
+	forkURL := fork.CloneURL
+	if originURL, err := git.GetRemoteURL(); err == nil && !strings.HasPrefix(originURL, "http") {
+		forkURL = fork.SSHURL
+	}
+
+	if err := git.AddRemote(github.ForkRemote, forkURL); err != nil {
+		return nil, err
+	}
+	ui.Success(fmt.Sprintf("Added remote '%s': %s", github.ForkRemote, forkURL))
+
+	rc.HeadOwner, rc.HeadRepo, rc.HeadRemote = fork.Owner, fork.Name, github.ForkRemote
+	return rc, nil
+}
+
+// deleteHeadBranch deletes the remote head branch of a PR, which may live in a fork
+func deleteHeadBranch(ghClient *github.Client, owner, repo string, pr *github.PullRequest) error {
+	// 通过 API 删除：origin 不一定是 PR 所在的仓库
+	headOwner, headRepo := pr.HeadRepository(owner, repo)
+	return ghClient.DeleteBranch(headOwner, headRepo, pr.Head)
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+)
+
+// reportCommitError explains a failed commit, pointing at the hook that rejected it
+func reportCommitError(err error) {
+	var hookErr *git.HookError
+	if !errors.As(err, &hookErr) {
+		ui.Error(fmt.Sprintf("Failed to commit: %v", err))
+		return
+	}
+
+	ui.Error(fmt.Sprintf("Commit rejected by git hook (%s), see its output above", strings.Join(hookErr.Hooks, ", ")))
+	ui.Info("Fix the reported issues, or skip the hooks with --no-verify")
+}
+
+// runPreCommitCheck runs the pre-commit hook against the changes the commit
+// will contain, staged into a copy of the index so the real one is untouched.
+// It returns false when the hook fails.
+func runPreCommitCheck(plan stagingPlan) bool {
+	if !git.HookExists("pre-commit") {
+		return true
+	}
+
+	indexFile, err := git.CopyIndex()
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Skipping pre-commit check: %v", err))
+		return true
+	}
+	defer os.Remove(indexFile)
+
+	// 已暂存的内容直接检查，否则先在副本中暂存
+	if !plan.StagedOnly {
+		if err := git.StageInIndex(indexFile, plan.Pathspecs); err != nil {
+			ui.Warning(fmt.Sprintf("Skipping pre-commit check: %v", err))
+			return true
+		}
+	}
+
+	ui.Info("Running pre-commit hook...")
+	if err := git.RunHook("pre-commit", indexFile); err != nil {
+		ui.Error("pre-commit hook failed, see its output above. Nothing was changed")
+		ui.Info("Fix the reported issues and rerun the command, or skip the hooks with --no-verify")
+		return false
+	}
+
+	ui.Success("pre-commit hook passed")
+	return true
+}
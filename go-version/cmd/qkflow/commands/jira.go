@@ -50,6 +50,9 @@ var jiraListCmd = &cobra.Command{
 			fmt.Printf("Project: %s\n", mapping.ProjectKey)
 			fmt.Printf("  PR Created → %s\n", mapping.PRCreatedStatus)
 			fmt.Printf("  PR Merged  → %s\n", mapping.PRMergedStatus)
+			if mapping.PRRevertedStatus != "" {
+				fmt.Printf("  PR Reverted → %s\n", mapping.PRRevertedStatus)
+			}
 			fmt.Println()
 		}
 	},
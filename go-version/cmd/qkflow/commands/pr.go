@@ -1,6 +1,12 @@
 package commands
 
 import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -14,5 +20,94 @@ func init() {
 	prCmd.AddCommand(prCreateCmd)
 	prCmd.AddCommand(prMergeCmd)
 	prCmd.AddCommand(prApproveCmd)
+	prCmd.AddCommand(prReviewCmd)
+	prCmd.AddCommand(prEditCmd)
+	prCmd.AddCommand(prCloseCmd)
+	prCmd.AddCommand(prReopenCmd)
+	prCmd.AddCommand(prCheckoutCmd)
+	prCmd.AddCommand(prBackportCmd)
+	prCmd.AddCommand(prRevertCmd)
+	prCmd.AddCommand(prConflictsCmd)
+}
+
+// prRefHelp describes the accepted PR reference formats for command help texts
+const prRefHelp = `  [pr]  PR number (e.g., 123 or #123), full GitHub PR URL
+        (e.g., https://github.com/owner/repo/pull/123), owner/repo#123,
+        Jira ticket (e.g., PROJ-123) or branch name.
+        Omit to auto-detect from current branch`
+
+// resolvePRArg resolves the PR referenced by args (or the current branch),
+// asking the user to pick one from the list when it can't be determined.
+// It returns nil after printing the reason when the PR could not be resolved.
+func resolvePRArg(ghClient *github.Client, args []string, action string, includeClosed bool) *github.ResolvedPR {
+	arg := ""
+	if len(args) > 0 {
+		arg = args[0]
+	}
+
+	opts := github.ResolveOptions{
+		IncludeClosed: includeClosed,
+		Select: func(reason string, candidates []github.PullRequest) (*github.PullRequest, error) {
+			return promptSelectPR(reason, candidates, action)
+		},
+	}
+
+	resolved, err := ghClient.ResolvePR(arg, opts)
+	if err != nil {
+		if errors.Is(err, github.ErrPRSelectionCancelled) {
+			ui.Info(fmt.Sprintf("%s cancelled", capitalizeFirst(action)))
+			return nil
+		}
+		ui.Error(fmt.Sprintf("Failed to resolve PR: %v", err))
+		if errors.Is(err, github.ErrPRNotFound) {
+			ui.Info("Expected: PR number (e.g., '123'), GitHub URL (e.g., 'https://github.com/owner/repo/pull/123'), owner/repo#123, Jira ticket or branch")
+		}
+		return nil
+	}
+
+	if resolved.Ref.Kind != github.PRRefNumber {
+		ui.Success(fmt.Sprintf("Found %s/%s PR #%d: %s", resolved.Owner, resolved.Repo, resolved.PR.Number, resolved.PR.Title))
+	}
+
+	return resolved
 }
 
+// promptSelectPR lets the user pick one of the candidate PRs
+func promptSelectPR(reason string, candidates []github.PullRequest, action string) (*github.PullRequest, error) {
+	ui.Warning(reason)
+	fmt.Println()
+
+	ok, err := ui.PromptConfirm("Do you want to select a PR from the list?", true)
+	if err != nil {
+		if err.Error() == "interrupt" {
+			ui.Warning("Operation cancelled by user")
+			os.Exit(0)
+		}
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	options := make([]string, len(candidates))
+	for i, pr := range candidates {
+		options[i] = fmt.Sprintf("#%d - %s (%s)", pr.Number, pr.Title, pr.Head)
+	}
+
+	selected, err := ui.PromptSelect(fmt.Sprintf("Select a PR to %s:", action), options)
+	if err != nil {
+		if err.Error() == "interrupt" {
+			ui.Warning("Operation cancelled by user")
+			os.Exit(0)
+		}
+		return nil, fmt.Errorf("failed to select PR: %w", err)
+	}
+
+	for i, option := range options {
+		if option == selected {
+			return &candidates[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to find selected PR")
+}
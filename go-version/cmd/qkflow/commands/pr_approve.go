@@ -2,8 +2,6 @@ package commands
 
 import (
 	"fmt"
-	"os"
-	"strconv"
 	"strings"
 
 	"github.com/Wangggym/quick-workflow/internal/git"
@@ -13,12 +11,16 @@ import (
 )
 
 var (
-	approveAndMerge bool
-	approveComment  string
+	approveAndMerge    bool
+	approveComment     string
+	approveAuthor      string
+	approveLabels      []string
+	approveConcurrency int
+	approveSkipConfirm bool
 )
 
 var prApproveCmd = &cobra.Command{
-	Use:   "approve [pr-number|pr-url]",
+	Use:   "approve [pr...]",
 	Short: "Approve a PR and optionally merge it",
 	Long: `Approve a pull request and optionally merge it automatically:
   - Approve the PR on GitHub
@@ -26,92 +28,32 @@ var prApproveCmd = &cobra.Command{
   - Optionally auto-merge after approval
 
 Arguments:
-  [pr-number|pr-url]  PR number (e.g., 123) or full GitHub PR URL
-                      (e.g., https://github.com/owner/repo/pull/123)
-                      Omit to auto-detect from current branch
+` + prRefHelp + `
 
 Examples:
   qkflow pr approve 123                  # Approves with 👍
   qkflow pr approve 123 -c "LGTM!"      # Custom comment
   qkflow pr approve https://github.com/brain/planning-api/pull/2001
-  qkflow pr approve 123 -m               # Approve with 👍 and merge`,
-	Args: cobra.MaximumNArgs(1),
+  qkflow pr approve 123 -m               # Approve with 👍 and merge
+
+Batch mode (several PRs or a query, run concurrently):
+  qkflow pr approve 101 102 103 -m
+  qkflow pr approve --author dependabot --label deps -m
+  qkflow pr approve --author dependabot -m --yes --concurrency 8`,
+	Args: cobra.ArbitraryArgs,
 	Run:  runPRApprove,
 }
 
 func init() {
 	prApproveCmd.Flags().BoolVarP(&approveAndMerge, "merge", "m", false, "Automatically merge the PR after approval")
 	prApproveCmd.Flags().StringVarP(&approveComment, "comment", "c", "", "Add a comment with the approval (default: 👍)")
+	prApproveCmd.Flags().StringVar(&approveAuthor, "author", "", "Batch mode: select open PRs by author (e.g., dependabot)")
+	prApproveCmd.Flags().StringSliceVar(&approveLabels, "label", []string{}, "Batch mode: select open PRs having all these labels")
+	prApproveCmd.Flags().IntVar(&approveConcurrency, "concurrency", 4, "Batch mode: number of PRs processed at the same time")
+	prApproveCmd.Flags().BoolVarP(&approveSkipConfirm, "yes", "y", false, "Batch mode: skip the confirmation prompt")
 }
 
 func runPRApprove(cmd *cobra.Command, args []string) {
-	var owner, repo string
-	var prNumber int
-	var err error
-
-	// 如果提供了参数，检查是否是 URL 格式
-	if len(args) > 0 {
-		arg := args[0]
-		
-		// 检查是否是 GitHub PR URL
-		if github.IsPRURL(arg) {
-			ui.Info("Detected GitHub PR URL, parsing...")
-			owner, repo, prNumber, err = github.ParsePRFromURL(arg)
-			if err != nil {
-				ui.Error(fmt.Sprintf("Failed to parse PR URL: %v", err))
-				return
-			}
-			ui.Success(fmt.Sprintf("Parsed: %s/%s PR #%d", owner, repo, prNumber))
-		} else {
-			// 尝试作为 PR 号解析
-			prNumber, err = strconv.Atoi(arg)
-			if err != nil {
-				ui.Error(fmt.Sprintf("Invalid PR number or URL: %s", arg))
-				ui.Info("Expected: PR number (e.g., '123') or GitHub URL (e.g., 'https://github.com/owner/repo/pull/123')")
-				return
-			}
-			
-			// PR 号格式，需要从本地仓库获取 owner/repo
-			if !git.IsGitRepository() {
-				ui.Error("Not a git repository. When using PR number, you must be in a git repository.")
-				ui.Info("Alternatively, use the full GitHub PR URL: https://github.com/owner/repo/pull/NUMBER")
-				return
-			}
-			
-			remoteURL, err := git.GetRemoteURL()
-			if err != nil {
-				ui.Error(fmt.Sprintf("Failed to get remote URL: %v", err))
-				return
-			}
-			
-			owner, repo, err = github.ParseRepositoryFromURL(remoteURL)
-			if err != nil {
-				ui.Error(fmt.Sprintf("Failed to parse repository: %v", err))
-				return
-			}
-		}
-	} else {
-		// 没有提供参数，使用原有的自动检测逻辑
-		// 检查是否在 Git 仓库中
-		if !git.IsGitRepository() {
-			ui.Error("Not a git repository")
-			return
-		}
-
-		// 获取仓库信息
-		remoteURL, err := git.GetRemoteURL()
-		if err != nil {
-			ui.Error(fmt.Sprintf("Failed to get remote URL: %v", err))
-			return
-		}
-
-		owner, repo, err = github.ParseRepositoryFromURL(remoteURL)
-		if err != nil {
-			ui.Error(fmt.Sprintf("Failed to parse repository: %v", err))
-			return
-		}
-	}
-
 	// 创建 GitHub 客户端
 	ghClient, err := github.NewClient()
 	if err != nil {
@@ -119,93 +61,19 @@ func runPRApprove(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// 如果还没有 PR 号，尝试自动检测或让用户选择
-	if prNumber == 0 {
-		// 尝试获取当前分支的 PR
-		currentBranch, err := git.GetCurrentBranch()
-		if err == nil && currentBranch != "" {
-			ui.Info(fmt.Sprintf("Checking for PR from current branch: %s", currentBranch))
-
-			// 先尝试 open 状态的 PR
-			prs, err := ghClient.ListPullRequests(owner, repo, "open", "")
-			if err == nil {
-				for _, pr := range prs {
-					if pr.Head == currentBranch {
-						prNumber = pr.Number
-						ui.Success(fmt.Sprintf("Found PR #%d: %s", pr.Number, pr.Title))
-						break
-					}
-				}
-			}
-
-			// 如果还是没找到，提示用户
-			if prNumber == 0 {
-				ui.Warning(fmt.Sprintf("No PR found for branch: %s", currentBranch))
-				fmt.Println()
-
-				// 询问用户是否从列表选择
-				manually, err := ui.PromptConfirm("Do you want to select a PR from the list?", true)
-				if err != nil || !manually {
-					ui.Info("Approve cancelled")
-					return
-				}
-			}
-		}
-
-		// 如果用户选择手动选择或没有当前分支
-		if prNumber == 0 {
-			prs, err := ghClient.ListPullRequests(owner, repo, "open", "")
-			if err != nil {
-				ui.Error(fmt.Sprintf("Failed to list PRs: %v", err))
-				return
-			}
-
-			if len(prs) == 0 {
-				ui.Error("No open pull requests found")
-				return
-			}
-
-			// 构建选择列表
-			prOptions := make([]string, len(prs))
-			for i, pr := range prs {
-				prOptions[i] = fmt.Sprintf("#%d - %s", pr.Number, pr.Title)
-			}
-
-			selected, err := ui.PromptSelect("Select a PR to approve:", prOptions)
-			if err != nil {
-				if err.Error() == "interrupt" {
-					ui.Warning("Operation cancelled by user")
-					os.Exit(0)
-				}
-				ui.Error(fmt.Sprintf("Failed to select PR: %v", err))
-				return
-			}
-
-			// 从选择中提取 PR 号
-			var selectedPR *github.PullRequest
-			for i, option := range prOptions {
-				if option == selected {
-					selectedPR = &prs[i]
-					break
-				}
-			}
-
-			if selectedPR != nil {
-				prNumber = selectedPR.Number
-			} else {
-				ui.Error("Failed to find selected PR")
-				return
-			}
-		}
+	// 多个 PR 或按条件筛选时进入批量模式
+	if len(args) > 1 || approveAuthor != "" || len(approveLabels) > 0 {
+		runPRApproveBatch(ghClient, args)
+		return
 	}
 
-	// 获取 PR 信息
-	ui.Info(fmt.Sprintf("Fetching PR #%d...", prNumber))
-	pr, err := ghClient.GetPullRequest(owner, repo, prNumber)
-	if err != nil {
-		ui.Error(fmt.Sprintf("Failed to get PR: %v", err))
+	// 解析 PR（编号、URL、owner/repo#123、Jira ticket、分支或当前分支）
+	resolved := resolvePRArg(ghClient, args, "approve", false)
+	if resolved == nil {
 		return
 	}
+	owner, repo, pr := resolved.Owner, resolved.Repo, resolved.PR
+	prNumber := pr.Number
 
 	ui.Info(fmt.Sprintf("PR: %s", pr.Title))
 	ui.Info(fmt.Sprintf("Branch: %s -> %s", pr.Head, pr.Base))
@@ -278,8 +146,8 @@ func runPRApprove(cmd *cobra.Command, args []string) {
 		ui.Success("🎉 PR merged successfully!")
 
 		// 删除远程分支
-		ui.Info(fmt.Sprintf("Deleting remote branch %s...", pr.Head))
-		if err := git.DeleteRemoteBranch(pr.Head); err != nil {
+		ui.Info(fmt.Sprintf("Deleting remote branch %s...", pr.HeadLabel(owner, repo)))
+		if err := deleteHeadBranch(ghClient, owner, repo, pr); err != nil {
 			ui.Warning(fmt.Sprintf("Failed to delete remote branch: %v (may already be deleted)", err))
 		} else {
 			ui.Success("Remote branch deleted")
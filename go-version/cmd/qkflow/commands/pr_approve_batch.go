@@ -0,0 +1,230 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+)
+
+// batchResult is the outcome of processing one PR in batch mode
+type batchResult struct {
+	Ref      string
+	Owner    string
+	Repo     string
+	PR       *github.PullRequest
+	Approved string
+	Merged   string
+	Err      error
+}
+
+func (r *batchResult) name() string {
+	if r.PR == nil {
+		return r.Ref
+	}
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.PR.Number)
+}
+
+// runPRApproveBatch approves (and optionally merges) several PRs concurrently
+func runPRApproveBatch(ghClient *github.Client, args []string) {
+	results := collectBatchPRs(ghClient, args)
+
+	pending := make([]*batchResult, 0, len(results))
+	for _, r := range results {
+		if r.Err == nil {
+			pending = append(pending, r)
+		}
+	}
+
+	if len(pending) == 0 {
+		printBatchResults(results)
+		ui.Error("No pull requests to process")
+		return
+	}
+
+	action := "approve"
+	if approveAndMerge {
+		action = "approve and merge"
+	}
+
+	fmt.Println()
+	ui.Info(fmt.Sprintf("Pull requests to %s:", action))
+	for _, r := range pending {
+		fmt.Printf("  %s  %s (%s)\n", r.name(), r.PR.Title, r.PR.Author)
+	}
+	fmt.Println()
+
+	if !approveSkipConfirm {
+		ok, err := ui.PromptConfirm(fmt.Sprintf("Proceed to %s %d PR(s)?", action, len(pending)), true)
+		if err != nil {
+			if err.Error() == "interrupt" {
+				ui.Warning("Operation cancelled by user")
+				os.Exit(0)
+			}
+			ui.Error(fmt.Sprintf("Failed to confirm: %v", err))
+			return
+		}
+		if !ok {
+			ui.Info("Approve cancelled")
+			return
+		}
+	}
+
+	comment := approveComment
+	if comment == "" {
+		comment = "👍"
+	}
+
+	concurrency := approveConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// 使用带缓冲的 channel 限制并发数
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, r := range pending {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *batchResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			processBatchPR(ghClient, r, comment)
+			if r.Err != nil {
+				fmt.Printf("❌ %s: %v\n", r.name(), r.Err)
+			} else {
+				fmt.Printf("✅ %s done\n", r.name())
+			}
+		}(r)
+	}
+	wg.Wait()
+
+	printBatchResults(results)
+}
+
+// collectBatchPRs resolves the PR arguments and query flags into a de-duplicated list
+func collectBatchPRs(ghClient *github.Client, args []string) []*batchResult {
+	results := make([]*batchResult, 0)
+	seen := make(map[string]bool)
+
+	add := func(r *batchResult) {
+		if r.PR != nil {
+			if seen[r.name()] {
+				return
+			}
+			seen[r.name()] = true
+		}
+		results = append(results, r)
+	}
+
+	for _, arg := range args {
+		resolved, err := ghClient.ResolvePR(arg, github.ResolveOptions{})
+		if err != nil {
+			add(&batchResult{Ref: arg, Err: err})
+			continue
+		}
+		r := &batchResult{Ref: arg, Owner: resolved.Owner, Repo: resolved.Repo, PR: resolved.PR}
+		if resolved.PR.State != "open" {
+			r.Err = fmt.Errorf("PR is not open (state: %s)", resolved.PR.State)
+		}
+		add(r)
+	}
+
+	if approveAuthor != "" || len(approveLabels) > 0 {
+		owner, repo, err := github.GetCurrentRepository()
+		if err != nil {
+			add(&batchResult{Ref: "query", Err: fmt.Errorf("cannot determine repository: %w", err)})
+			return results
+		}
+
+		ui.Info(fmt.Sprintf("Searching open PRs in %s/%s...", owner, repo))
+		prs, err := ghClient.FilterPullRequests(owner, repo, approveAuthor, approveLabels)
+		if err != nil {
+			add(&batchResult{Ref: "query", Err: err})
+			return results
+		}
+		if len(prs) == 0 {
+			ui.Warning("No open PRs match the query")
+		}
+		for i := range prs {
+			add(&batchResult{Ref: fmt.Sprintf("#%d", prs[i].Number), Owner: owner, Repo: repo, PR: &prs[i]})
+		}
+	}
+
+	return results
+}
+
+// processBatchPR approves one PR and merges it when requested, recording the outcome
+func processBatchPR(ghClient *github.Client, r *batchResult, comment string) {
+	r.Approved = "✅"
+	if err := ghClient.ApprovePullRequest(r.Owner, r.Repo, r.PR.Number, comment); err != nil {
+		// 422：自己的 PR 或已批准，合并模式下可以继续
+		if strings.Contains(err.Error(), "422") && approveAndMerge {
+			r.Approved = "skipped"
+		} else {
+			r.Approved = "❌"
+			r.Err = err
+			return
+		}
+	}
+
+	if !approveAndMerge {
+		r.Merged = "-"
+		return
+	}
+
+	r.Merged = "❌"
+	if _, err := ghClient.IsPRMergeable(r.Owner, r.Repo, r.PR.Number); err != nil {
+		r.Err = err
+		return
+	}
+
+	if err := ghClient.MergePullRequest(r.Owner, r.Repo, r.PR.Number, r.PR.Title); err != nil {
+		r.Err = err
+		return
+	}
+	r.Merged = "✅"
+
+	// 远程分支删除失败不影响结果
+	headOwner, headRepo := r.PR.HeadRepository(r.Owner, r.Repo)
+	_ = ghClient.DeleteBranch(headOwner, headRepo, r.PR.Head)
+}
+
+// printBatchResults prints a per-PR summary table
+func printBatchResults(results []*batchResult) {
+	fmt.Println()
+	fmt.Printf("%-40s %-10s %-8s %s\n", "PR", "APPROVED", "MERGED", "RESULT")
+	fmt.Println(strings.Repeat("-", 80))
+
+	failed := 0
+	for _, r := range results {
+		status := ui.Green("ok")
+		if r.Err != nil {
+			failed++
+			status = ui.Red(firstLine(r.Err.Error()))
+		}
+		fmt.Printf("%-40s %-10s %-8s %s\n", truncateString(r.name(), 40), orDash(r.Approved), orDash(r.Merged), status)
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		ui.Warning(fmt.Sprintf("%d of %d PR(s) failed", failed, len(results)))
+	} else {
+		ui.Success(fmt.Sprintf("All %d PR(s) processed! 🎉", len(results)))
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func firstLine(s string) string {
+	return strings.SplitN(s, "\n", 2)[0]
+}
@@ -0,0 +1,448 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/jira"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/Wangggym/quick-workflow/internal/watcher"
+	"github.com/spf13/cobra"
+)
+
+var backportTargets []string
+
+var prBackportCmd = &cobra.Command{
+	Use:   "backport [pr] --to <branch>[,<branch>...]",
+	Short: "Backport a merged PR to release branches",
+	Long: `Cherry-pick the merge (or squash) commit of a merged pull request onto one
+or more target branches and open a PR for each of them. For a PR merged with
+rebase, all of its commits are cherry-picked.
+
+For every target a branch backport/<pr>-to-<target> is created from the
+remote target branch. The new PR references the original PR and links the
+same Jira ticket(s).
+
+When a cherry-pick stops on conflicts, qkflow leaves it in progress and
+prints how to continue. After resolving and running 'git cherry-pick
+--continue', run the same backport command again: targets that already
+have a backport PR are skipped and the resolved branch is pushed.
+
+Arguments:
+` + prRefHelp + `
+
+Examples:
+  qkflow pr backport 123 --to release/2.3
+  qkflow pr backport 123 --to release/2.3,release/2.2`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runPRBackport,
+}
+
+func init() {
+	prBackportCmd.Flags().StringSliceVar(&backportTargets, "to", nil, "Target branch(es) to backport to (comma-separated)")
+	prBackportCmd.MarkFlagRequired("to")
+}
+
+// backportResult is the outcome of backporting to one target branch
+type backportResult struct {
+	target string
+	branch string
+	prURL  string
+	status string
+}
+
+func runPRBackport(cmd *cobra.Command, args []string) {
+	if !git.IsGitRepository() {
+		ui.Error("Not a git repository")
+		return
+	}
+
+	if git.CherryPickInProgress() {
+		ui.Error("A cherry-pick is in progress")
+		ui.Info("Finish it with 'git cherry-pick --continue' (or 'git cherry-pick --abort') and run the command again")
+		return
+	}
+
+	hasChanges, err := git.HasUncommittedChanges()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to check git status: %v", err))
+		return
+	}
+	if hasChanges {
+		ui.Error("You have uncommitted changes, commit or stash them first")
+		return
+	}
+
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	resolved := resolvePRArg(ghClient, args, "backport", true)
+	if resolved == nil {
+		return
+	}
+	owner, repo, pr := resolved.Owner, resolved.Repo, resolved.PR
+
+	if !pr.Merged {
+		ui.Error(fmt.Sprintf("PR #%d is not merged (state: %s)", pr.Number, pr.State))
+		return
+	}
+	if pr.MergeCommitSHA == "" {
+		ui.Error(fmt.Sprintf("PR #%d has no merge commit", pr.Number))
+		return
+	}
+
+	rc, err := resolveRepoContext(false)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to get repository info: %v", err))
+		return
+	}
+	if !strings.EqualFold(rc.BaseOwner, owner) || !strings.EqualFold(rc.BaseRepo, repo) {
+		ui.Error(fmt.Sprintf("PR belongs to %s/%s but this clone is %s/%s", owner, repo, rc.BaseOwner, rc.BaseRepo))
+		return
+	}
+
+	originalBranch, err := git.GetCurrentBranch()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to get current branch: %v", err))
+		return
+	}
+
+	// 合并提交位于 PR 的目标分支上
+	ui.Info(fmt.Sprintf("Fetching %s/%s...", rc.BaseRemote, pr.Base))
+	if err := git.FetchBranch(rc.BaseRemote, pr.Base); err != nil {
+		ui.Error(fmt.Sprintf("Failed to fetch: %v", err))
+		return
+	}
+	if _, err := git.RevParse(pr.MergeCommitSHA); err != nil {
+		ui.Error(fmt.Sprintf("Merge commit %s not found locally: %v", shortSHA(pr.MergeCommitSHA), err))
+		return
+	}
+
+	tickets := linkedJiraTickets(owner, repo, pr)
+	commits := mergedCommits(ghClient, rc, pr)
+
+	results := make([]backportResult, 0, len(backportTargets))
+	conflicted := false
+	for _, target := range backportTargets {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+		if conflicted {
+			results = append(results, backportResult{target: target, status: "pending"})
+			continue
+		}
+
+		result := backportTo(ghClient, rc, pr, commits, target, tickets)
+		results = append(results, result)
+		if result.status == "conflict" {
+			conflicted = true
+		}
+	}
+
+	// 冲突时停留在 backport 分支上，方便用户解决
+	if !conflicted {
+		if err := git.CheckoutBranch(originalBranch); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to switch back to %s: %v", originalBranch, err))
+		}
+	}
+
+	created := make([]string, 0)
+	fmt.Println()
+	ui.Info(fmt.Sprintf("Backport of PR #%d:", pr.Number))
+	for _, r := range results {
+		switch r.status {
+		case "created", "exists":
+			fmt.Printf("  %s %s → %s\n", ui.Green("✓"), r.target, r.prURL)
+			if r.status == "created" {
+				created = append(created, fmt.Sprintf("- `%s`: %s", r.target, r.prURL))
+			}
+		case "conflict":
+			fmt.Printf("  %s %s → conflicts on %s\n", ui.Red("✗"), r.target, r.branch)
+		case "pending":
+			fmt.Printf("  %s %s → not started\n", ui.Yellow("…"), r.target)
+		default:
+			fmt.Printf("  %s %s → %s\n", ui.Red("✗"), r.target, r.status)
+		}
+	}
+
+	if len(created) > 0 {
+		comment := "Backported to:\n\n" + strings.Join(created, "\n")
+		if err := ghClient.AddPRComment(owner, repo, pr.Number, comment); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to comment on the original PR: %v", err))
+		}
+	}
+
+	if conflicted {
+		fmt.Println()
+		ui.Warning("Cherry-pick stopped on conflicts. To continue:")
+		fmt.Println("  1. Resolve the conflicts and 'git add' the files")
+		fmt.Println("  2. git cherry-pick --continue")
+		fmt.Printf("  3. qkflow pr backport %d --to %s\n", pr.Number, strings.Join(backportTargets, ","))
+		ui.Info("Or give up on this target with 'git cherry-pick --abort'")
+		return
+	}
+
+	fmt.Println()
+	ui.Success("All done! 🎉")
+}
+
+// backportBranchName returns the branch holding the backport of a PR to target
+func backportBranchName(number int, target string) string {
+	return fmt.Sprintf("backport/%d-to-%s", number, strings.ReplaceAll(target, "/", "-"))
+}
+
+// backportTo cherry-picks the PR's commits onto target and opens the backport PR
+func backportTo(ghClient *github.Client, rc *github.RepoContext, pr *github.PullRequest, commits []string, target string, tickets []string) backportResult {
+	owner, repo := rc.BaseOwner, rc.BaseRepo
+	branch := backportBranchName(pr.Number, target)
+	result := backportResult{target: target, branch: branch}
+
+	fmt.Println()
+	ui.Info(fmt.Sprintf("Backporting to %s...", target))
+
+	if existing, err := ghClient.FindPRsByBranch(owner, repo, rc.HeadRef(branch), "open"); err == nil && len(existing) > 0 {
+		ui.Info(fmt.Sprintf("Backport PR already exists: %s", existing[0].HTMLURL))
+		result.prURL, result.status = existing[0].HTMLURL, "exists"
+		return result
+	}
+
+	if err := git.FetchBranch(rc.BaseRemote, target); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to fetch %s: %v", target, err))
+		result.status = "target not found"
+		return result
+	}
+	remoteTarget := rc.BaseRemote + "/" + target
+
+	// 再次运行时，已解决冲突的分支直接推送
+	picked, err := checkoutResolvedBranch(ghClient, rc, branch, remoteTarget)
+	if err != nil {
+		ui.Warning(err.Error())
+		result.status = "checkout failed"
+		return result
+	}
+	if picked {
+		ui.Info(fmt.Sprintf("Reusing %s with the resolved cherry-pick", branch))
+	}
+
+	if !picked {
+		if err := git.CreateBranchFrom(branch, remoteTarget); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to create branch: %v", err))
+			result.status = "branch failed"
+			return result
+		}
+
+		ui.Info(fmt.Sprintf("Cherry-picking %s onto %s...", describeCommits(commits), branch))
+		if err := git.CherryPick(commits...); err != nil {
+			// 目标分支已包含的提交 cherry-pick 为空，跳过后继续
+			for err != nil && git.CherryPickInProgress() {
+				if dirty, derr := git.HasUncommittedChanges(); derr != nil || dirty {
+					result.status = "conflict"
+					return result
+				}
+				err = git.SkipCherryPick()
+			}
+			if err != nil {
+				ui.Warning(fmt.Sprintf("Cherry-pick failed: %v", err))
+				result.status = "cherry-pick failed"
+				return result
+			}
+			if picked, _ := git.CommitsBetween(remoteTarget, "HEAD"); len(picked) == 0 {
+				ui.Info(fmt.Sprintf("%s already contains the changes", target))
+				result.status = "already applied"
+				return result
+			}
+		}
+		ui.Success("Cherry-picked")
+	}
+
+	ui.Info(fmt.Sprintf("Pushing %s to %s...", branch, rc.HeadRemote))
+	if err := git.PushTo(rc.HeadRemote, branch); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to push: %v", err))
+		result.status = "push failed"
+		return result
+	}
+
+	newPR, err := ghClient.CreatePullRequest(github.CreatePullRequestInput{
+		Owner: owner,
+		Repo:  repo,
+		Title: fmt.Sprintf("%s (backport to %s)", pr.Title, target),
+		Body:  buildBackportBody(pr, commits, target, tickets),
+		Head:  rc.HeadRef(branch),
+		Base:  target,
+	})
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to create PR: %v", err))
+		result.status = "PR failed"
+		return result
+	}
+	ui.Success(fmt.Sprintf("Pull request created: %s", newPR.HTMLURL))
+	result.prURL, result.status = newPR.HTMLURL, "created"
+
+	linkBackportPR(rc, newPR, branch, tickets)
+	return result
+}
+
+// buildBackportBody builds the body of a backport PR
+func buildBackportBody(pr *github.PullRequest, commits []string, target string, tickets []string) string {
+	var body strings.Builder
+
+	body.WriteString(fmt.Sprintf("Backport of #%d to `%s`.\n\n", pr.Number, target))
+	body.WriteString(fmt.Sprintf("Original PR: %s\n", pr.HTMLURL))
+	if len(commits) == 1 {
+		body.WriteString(fmt.Sprintf("Cherry-picked commit: %s\n\n", commits[0]))
+	} else {
+		body.WriteString(fmt.Sprintf("Cherry-picked commits: %s\n\n", strings.Join(commits, ", ")))
+	}
+
+	if links := jiraLinks(tickets); len(links) > 0 {
+		body.WriteString(fmt.Sprintf("#### Jira Link:\n\n%s\n", strings.Join(links, "\n")))
+	}
+
+	return body.String()
+}
+
+// jiraLinks returns the browse URLs of the tickets
+func jiraLinks(tickets []string) []string {
+	links := make([]string, 0, len(tickets))
+	if len(tickets) == 0 {
+		return links
+	}
+
+	jiraClient, err := jira.NewClient()
+	if err != nil {
+		return links
+	}
+	for _, ticket := range tickets {
+		if url := jiraClient.GetJiraURL(ticket); url != "" {
+			links = append(links, url)
+		}
+	}
+	return links
+}
+
+// linkBackportPR links the backport PR to the Jira tickets and watches it
+func linkBackportPR(rc *github.RepoContext, pr *github.PullRequest, branch string, tickets []string) {
+	if len(tickets) > 0 {
+		if jiraClient, err := jira.NewClient(); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to create Jira client: %v", err))
+		} else {
+			for _, ticket := range tickets {
+				if err := jiraClient.AddPRLink(ticket, pr.HTMLURL); err != nil {
+					ui.Warning(fmt.Sprintf("Failed to add PR link to %s: %v", ticket, err))
+				} else {
+					ui.Success(fmt.Sprintf("Added PR link to %s", ticket))
+				}
+			}
+		}
+	}
+
+	watchingList, err := watcher.NewWatchingList()
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to load watching list: %v", err))
+		return
+	}
+
+	headOwner := ""
+	if rc.IsFork() {
+		headOwner = rc.HeadOwner
+	}
+
+	if err := watchingList.Add(watcher.WatchingPR{
+		PRNumber:    pr.Number,
+		Owner:       rc.BaseOwner,
+		Repo:        rc.BaseRepo,
+		Branch:      branch,
+		HeadOwner:   headOwner,
+		Title:       pr.Title,
+		PRURL:       pr.HTMLURL,
+		JiraTickets: tickets,
+	}); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to add PR to watching list: %v", err))
+	}
+}
+
+// mergedCommits returns the commits a merged PR added to its base branch,
+// oldest first: the merge or squash commit, or all commits of a rebase merge
+func mergedCommits(ghClient *github.Client, rc *github.RepoContext, pr *github.PullRequest) []string {
+	merge := []string{pr.MergeCommitSHA}
+	if parents, err := git.CommitParents(pr.MergeCommitSHA); err != nil || len(parents) != 1 {
+		return merge
+	}
+
+	count := pr.Commits
+	if count == 0 {
+		// 通过列表找到的 PR 没有提交数
+		if full, err := ghClient.GetPullRequest(rc.BaseOwner, rc.BaseRepo, pr.Number); err == nil {
+			count = full.Commits
+		}
+	}
+	if count <= 1 || pr.HeadSHA == "" {
+		return merge
+	}
+
+	// squash 和 rebase 合并的最后一个提交都只有一个父提交；
+	// rebase 合并时它与 PR 的最后一个提交改动相同
+	if _, err := git.RevParse(pr.HeadSHA + "^{commit}"); err != nil {
+		if err := git.FetchPullRequest(rc.BaseRemote, pr.Number); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to fetch the commits of PR #%d, using the merge commit only: %v", pr.Number, err))
+			return merge
+		}
+	}
+	if !git.SamePatch(pr.MergeCommitSHA, pr.HeadSHA) {
+		return merge
+	}
+
+	commits, err := git.CommitRange(fmt.Sprintf("%s~%d", pr.MergeCommitSHA, count), pr.MergeCommitSHA)
+	if err != nil || len(commits) != count {
+		ui.Warning(fmt.Sprintf("Could not find the %d rebased commits of PR #%d, using the merge commit only", count, pr.Number))
+		return merge
+	}
+	return commits
+}
+
+// describeCommits names a list of commits for messages
+func describeCommits(commits []string) string {
+	if len(commits) == 1 {
+		return shortSHA(commits[0])
+	}
+	return fmt.Sprintf("%d commits (%s..%s)", len(commits), shortSHA(commits[0]), shortSHA(commits[len(commits)-1]))
+}
+
+// checkoutResolvedBranch checks out branch when an earlier run left it with
+// resolved conflicts: it has commits ahead of base and no PR was ever opened
+// from it. A branch whose PR was merged or closed is not reused.
+func checkoutResolvedBranch(ghClient *github.Client, rc *github.RepoContext, branch, base string) (bool, error) {
+	if !git.BranchExists(branch) {
+		return false, nil
+	}
+	if ahead, err := git.CommitsBetween(base, branch); err != nil || len(ahead) == 0 {
+		return false, nil
+	}
+
+	prs, err := ghClient.FindPRsByBranch(rc.BaseOwner, rc.BaseRepo, rc.HeadRef(branch), "all")
+	if err != nil {
+		return false, fmt.Errorf("failed to find the PRs of %s: %w", branch, err)
+	}
+	if len(prs) > 0 {
+		ui.Info(fmt.Sprintf("%s is left from PR #%d (%s), recreating it", branch, prs[0].Number, prs[0].State))
+		return false, nil
+	}
+
+	if err := git.CheckoutBranch(branch); err != nil {
+		return false, fmt.Errorf("failed to checkout %s: %w", branch, err)
+	}
+	return true, nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/jira"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/Wangggym/quick-workflow/pkg/config"
+)
+
+// selectBaseBranch decides the PR base branch, in order of precedence:
+// the --base flag, a ticket-type rule, a picker over branches matching the
+// configured patterns, and finally the default branch of the remote.
+func selectBaseBranch(remote, flagBase string, issue *jira.Issue) (string, error) {
+	if flagBase != "" {
+		return flagBase, nil
+	}
+
+	defaultBranch, err := git.GetDefaultBranchOf(remote)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to detect default branch, using 'main': %v", err))
+		defaultBranch = "main"
+	}
+
+	cfg := config.Get()
+	if cfg == nil || (len(cfg.BaseBranchRules) == 0 && len(cfg.BaseBranchPatterns) == 0) {
+		return defaultBranch, nil
+	}
+
+	remoteBranches, err := git.ListRemoteBranches(remote)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to list remote branches: %v", err))
+		return defaultBranch, nil
+	}
+
+	if issue != nil {
+		if branch, reason := matchBaseBranchRule(cfg.BaseBranchRules, issue, remoteBranches); branch != "" {
+			ui.Info(fmt.Sprintf("Base branch %s selected by rule: %s", branch, reason))
+			return branch, nil
+		}
+	}
+
+	candidates := matchBranchPatterns(cfg.BaseBranchPatterns, remoteBranches, defaultBranch)
+	if len(candidates) <= 1 {
+		return defaultBranch, nil
+	}
+
+	selected, err := ui.PromptSelect("Select the base branch:", candidates)
+	if err != nil {
+		if err.Error() == "interrupt" {
+			ui.Warning("Operation cancelled by user")
+			os.Exit(0)
+		}
+		return "", fmt.Errorf("failed to select base branch: %w", err)
+	}
+
+	return selected, nil
+}
+
+// matchBaseBranchRule returns the base branch of the first rule matching the ticket
+// whose branch exists on the remote, along with a description of the match
+func matchBaseBranchRule(rules []config.BaseBranchRule, issue *jira.Issue, remoteBranches []string) (string, string) {
+	exists := make(map[string]bool, len(remoteBranches))
+	for _, b := range remoteBranches {
+		exists[b] = true
+	}
+
+	for _, rule := range rules {
+		if rule.Branch == "" || (rule.IssueType != "" && !strings.EqualFold(rule.IssueType, issue.Type)) {
+			continue
+		}
+
+		if !strings.Contains(rule.Branch, "{fix_version}") {
+			if exists[rule.Branch] {
+				return rule.Branch, fmt.Sprintf("%s ticket", issue.Type)
+			}
+			continue
+		}
+
+		for _, version := range issue.FixVersions {
+			branch := strings.ReplaceAll(rule.Branch, "{fix_version}", version)
+			if exists[branch] {
+				return branch, fmt.Sprintf("%s ticket with fix version %s", issue.Type, version)
+			}
+		}
+		if len(issue.FixVersions) > 0 {
+			ui.Warning(fmt.Sprintf("No remote branch for %s with fix version(s) %s", rule.Branch, strings.Join(issue.FixVersions, ", ")))
+		}
+	}
+
+	return "", ""
+}
+
+// matchBranchPatterns lists the remote branches matching any pattern, default branch first
+func matchBranchPatterns(patterns, remoteBranches []string, defaultBranch string) []string {
+	matched := make([]string, 0)
+	for _, branch := range remoteBranches {
+		if branch == defaultBranch {
+			continue
+		}
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, branch); ok {
+				matched = append(matched, branch)
+				break
+			}
+		}
+	}
+
+	// 版本分支按版本号倒序，最新的版本在前（release/2.10 在 release/2.9 之前）
+	sort.SliceStable(matched, func(i, j int) bool {
+		return compareVersionNames(matched[i], matched[j]) > 0
+	})
+	return append([]string{defaultBranch}, matched...)
+}
+
+// compareVersionNames compares branch names, treating runs of digits as numbers
+func compareVersionNames(a, b string) int {
+	for a != "" && b != "" {
+		partA, restA := splitVersionPart(a)
+		partB, restB := splitVersionPart(b)
+
+		if isDigits(partA) && isDigits(partB) {
+			// 去掉前导零后先比较长度，避免整数溢出
+			numA, numB := strings.TrimLeft(partA, "0"), strings.TrimLeft(partB, "0")
+			if len(numA) != len(numB) {
+				return len(numA) - len(numB)
+			}
+			if c := strings.Compare(numA, numB); c != 0 {
+				return c
+			}
+		} else if c := strings.Compare(partA, partB); c != 0 {
+			return c
+		}
+		a, b = restA, restB
+	}
+	return len(a) - len(b)
+}
+
+// splitVersionPart splits off the leading run of digits or of other characters
+func splitVersionPart(s string) (string, string) {
+	digit := s[0] >= '0' && s[0] <= '9'
+	i := 1
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9') == digit {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// isDigits reports whether a part returned by splitVersionPart is a number
+func isDigits(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
+
+// validateBaseBranch checks that the commit the PR starts from is based on the
+// base branch, so the PR doesn't pull in unrelated commits. It returns false
+// when the user chooses not to continue.
+func validateBaseBranch(remote, base string) bool {
+	if err := git.FetchBranch(remote, base); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to fetch %s/%s: %v", remote, base, err))
+		return true
+	}
+
+	remoteBase := remote + "/" + base
+	commits, err := git.CommitsBetween(remoteBase, "HEAD")
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to compare with %s: %v", remoteBase, err))
+		return true
+	}
+	if len(commits) == 0 {
+		return true
+	}
+
+	ui.Warning(fmt.Sprintf("The current commit is not based on %s; the PR would include %d extra commit(s):", remoteBase, len(commits)))
+	for i, c := range commits {
+		if i == 10 {
+			fmt.Printf("  ... and %d more\n", len(commits)-10)
+			break
+		}
+		fmt.Printf("  %s\n", c)
+	}
+	ui.Info(fmt.Sprintf("Tip: git stash && git checkout -B %s %s && git stash pop, then run 'qkflow pr create' again", base, remoteBase))
+
+	ok, err := ui.PromptConfirm("Continue anyway?", false)
+	if err != nil {
+		if err.Error() == "interrupt" {
+			ui.Warning("Operation cancelled by user")
+			os.Exit(0)
+		}
+		return false
+	}
+	return ok
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/jira"
+	"github.com/Wangggym/quick-workflow/pkg/config"
+)
+
+func TestCompareVersionNames(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int // 只比较符号
+	}{
+		{"release/2.10", "release/2.9", 1},
+		{"release/2.9", "release/2.10", -1},
+		{"release/2.9", "release/2.9", 0},
+		{"release/2.09", "release/2.9", 0},
+		{"release/2.010", "release/2.9", 1},
+		{"release/10.0", "release/9.99", 1},
+		{"release/2.3.1", "release/2.3", 1},
+		{"release/2.3", "release/2.3-rc1", -1},
+		{"hotfix/1.0", "release/1.0", -1},
+		{"release/99999999999999999999", "release/9", 1},
+	}
+
+	for _, tt := range tests {
+		got := compareVersionNames(tt.a, tt.b)
+		if sign(got) != tt.want {
+			t.Errorf("compareVersionNames(%q, %q) = %d, want sign %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
+
+func TestMatchBranchPatterns(t *testing.T) {
+	remote := []string{"main", "release/2.9", "feature/x", "release/2.10", "release/1.12", "hotfix/2.10.1"}
+
+	got := matchBranchPatterns([]string{"release/*", "hotfix/*"}, remote, "main")
+	want := []string{"main", "release/2.10", "release/2.9", "release/1.12", "hotfix/2.10.1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchBranchPatterns() = %v, want %v", got, want)
+	}
+}
+
+func TestMatchBaseBranchRule(t *testing.T) {
+	rules := []config.BaseBranchRule{
+		{IssueType: "Bug", Branch: "release/{fix_version}"},
+		{IssueType: "Hotfix", Branch: "hotfix"},
+		{Branch: "develop"},
+	}
+	remote := []string{"main", "develop", "release/2.9", "release/2.10"}
+
+	tests := []struct {
+		name  string
+		rules []config.BaseBranchRule
+		issue jira.Issue
+		want  string
+	}{
+		{name: "Fix version", rules: rules, issue: jira.Issue{Type: "Bug", FixVersions: []string{"2.10"}}, want: "release/2.10"},
+		{name: "Issue type ignores case", rules: rules, issue: jira.Issue{Type: "bug", FixVersions: []string{"2.9"}}, want: "release/2.9"},
+		{name: "First fix version with a branch", rules: rules, issue: jira.Issue{Type: "Bug", FixVersions: []string{"3.0", "2.9"}}, want: "release/2.9"},
+		{name: "No branch for the fix version", rules: rules, issue: jira.Issue{Type: "Bug", FixVersions: []string{"3.0"}}, want: "develop"},
+		{name: "No fix version", rules: rules, issue: jira.Issue{Type: "Bug"}, want: "develop"},
+		{name: "Missing branch", rules: rules, issue: jira.Issue{Type: "Hotfix"}, want: "develop"},
+		{name: "Rule for any type", rules: rules, issue: jira.Issue{Type: "Story"}, want: "develop"},
+		{name: "No matching rule", rules: rules[:2], issue: jira.Issue{Type: "Story", FixVersions: []string{"2.9"}}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := tt.issue
+			if got, _ := matchBaseBranchRule(tt.rules, &issue, remote); got != tt.want {
+				t.Errorf("matchBaseBranchRule() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
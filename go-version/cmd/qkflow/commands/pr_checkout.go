@@ -0,0 +1,293 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkoutWorktree bool
+	checkoutPath     string
+	checkoutClean    bool
+	checkoutForce    bool
+	checkoutYes      bool
+)
+
+var prCheckoutCmd = &cobra.Command{
+	Use:   "checkout [pr]",
+	Short: "Check out a PR locally, optionally in a separate worktree",
+	Long: `Fetch the head of a pull request (including PRs from forks) and check it out
+in a local branch that tracks the PR branch.
+
+With --worktree the branch is checked out in a separate directory using
+'git worktree', leaving your current branch and uncommitted work untouched.
+Worktrees are created next to the repository in <repo>-worktrees/pr-<number>.
+
+Use --clean to remove worktrees of PRs that have been merged or closed.
+
+Arguments:
+` + prRefHelp + `
+
+Examples:
+  qkflow pr checkout 123
+  qkflow pr checkout https://github.com/owner/repo/pull/123 --worktree
+  qkflow pr checkout --clean          # remove worktrees of merged/closed PRs`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runPRCheckout,
+}
+
+func init() {
+	prCheckoutCmd.Flags().BoolVarP(&checkoutWorktree, "worktree", "w", false, "Check out in a separate git worktree")
+	prCheckoutCmd.Flags().StringVar(&checkoutPath, "path", "", "Directory of the worktree (implies --worktree)")
+	prCheckoutCmd.Flags().BoolVar(&checkoutClean, "clean", false, "Remove worktrees of merged or closed PRs")
+	prCheckoutCmd.Flags().BoolVarP(&checkoutForce, "force", "f", false, "With --clean, also remove worktrees with uncommitted changes")
+	prCheckoutCmd.Flags().BoolVarP(&checkoutYes, "yes", "y", false, "With --clean, skip the confirmation prompt")
+}
+
+func runPRCheckout(cmd *cobra.Command, args []string) {
+	if !git.IsGitRepository() {
+		ui.Error("Not a git repository")
+		return
+	}
+
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	if checkoutClean {
+		cleanPRWorktrees(ghClient)
+		return
+	}
+
+	resolved := resolvePRArg(ghClient, args, "check out", false)
+	if resolved == nil {
+		return
+	}
+	owner, repo, pr := resolved.Owner, resolved.Repo, resolved.PR
+
+	// 只能从 base 仓库的 remote 拉取 PR，确保 PR 属于当前仓库
+	rc, err := github.DetectRepoContext()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to get repository info: %v", err))
+		return
+	}
+	if !strings.EqualFold(rc.BaseOwner, owner) || !strings.EqualFold(rc.BaseRepo, repo) {
+		ui.Error(fmt.Sprintf("PR belongs to %s/%s but this clone is %s/%s", owner, repo, rc.BaseOwner, rc.BaseRepo))
+		return
+	}
+
+	ui.Info(fmt.Sprintf("Fetching PR #%d (%s)...", pr.Number, pr.HeadLabel(owner, repo)))
+	if err := git.FetchPullRequest(rc.BaseRemote, pr.Number); err != nil {
+		ui.Error(fmt.Sprintf("Failed to fetch PR: %v", err))
+		return
+	}
+
+	branch, remote := checkoutBranchName(rc, pr)
+
+	if !git.BranchExists(branch) {
+		if err := git.CreateBranchAt(branch, "FETCH_HEAD"); err != nil {
+			ui.Error(fmt.Sprintf("Failed to create branch: %v", err))
+			return
+		}
+		if pr.HeadCloneURL == "" {
+			ui.Warning("PR head repository is gone, the branch does not track a remote")
+		} else if err := git.SetBranchUpstream(branch, remote, pr.Head); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to set upstream: %v", err))
+		}
+		ui.Success(fmt.Sprintf("Created branch %s", branch))
+	}
+
+	worktrees, err := git.ListWorktrees()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to list worktrees: %v", err))
+		return
+	}
+
+	// 分支已在某个 worktree 中检出时直接在那里更新
+	for _, wt := range worktrees {
+		if wt.Branch == branch {
+			// FETCH_HEAD 属于当前 worktree，先解析为提交
+			sha, err := git.RevParse("FETCH_HEAD")
+			if err == nil {
+				err = git.MergeFastForward(wt.Path, sha)
+			}
+			if err != nil {
+				ui.Warning(fmt.Sprintf("Could not fast-forward %s: %v", branch, err))
+			}
+			ui.Success(fmt.Sprintf("PR #%d is checked out in %s", pr.Number, wt.Path))
+			if wt.Path != worktrees[0].Path {
+				fmt.Printf("\n  cd %s\n\n", wt.Path)
+			}
+			return
+		}
+	}
+
+	if err := git.UpdateBranchRef(branch, "FETCH_HEAD"); err != nil {
+		ui.Warning(fmt.Sprintf("Local branch %s has diverged from the PR, keeping local commits", branch))
+	}
+
+	if checkoutWorktree || checkoutPath != "" {
+		path := checkoutPath
+		if path == "" {
+			path = prWorktreePath(worktrees[0].Path, pr.Number)
+		}
+
+		ui.Info(fmt.Sprintf("Creating worktree at %s...", path))
+		if err := git.AddWorktree(path, branch); err != nil {
+			ui.Error(fmt.Sprintf("Failed to create worktree: %v", err))
+			return
+		}
+
+		ui.Success(fmt.Sprintf("Checked out PR #%d in a worktree: %s", pr.Number, pr.Title))
+		fmt.Printf("\n  cd %s\n\n", path)
+		ui.Info("Remove it with 'qkflow pr checkout --clean' once the PR is merged")
+		return
+	}
+
+	hasChanges, err := git.HasUncommittedChanges()
+	if err == nil && hasChanges {
+		ui.Warning("You have uncommitted changes, they will be carried to the PR branch")
+		ui.Info("Tip: use --worktree to keep your current branch untouched")
+		ok, err := ui.PromptConfirm("Continue?", false)
+		if err != nil || !ok {
+			ui.Info("Checkout cancelled")
+			return
+		}
+	}
+
+	if err := git.CheckoutBranch(branch); err != nil {
+		ui.Error(fmt.Sprintf("Failed to checkout branch: %v", err))
+		return
+	}
+
+	ui.Success(fmt.Sprintf("Checked out PR #%d on branch %s: %s", pr.Number, branch, pr.Title))
+}
+
+// checkoutBranchName returns the local branch name for the PR and the remote it tracks
+func checkoutBranchName(rc *github.RepoContext, pr *github.PullRequest) (string, string) {
+	if !pr.IsCrossRepository(rc.BaseOwner, rc.BaseRepo) {
+		return pr.Head, rc.BaseRemote
+	}
+	// 自己 fork 中的分支跟踪 fork 的 remote
+	if !pr.IsCrossRepository(rc.HeadOwner, rc.HeadRepo) {
+		return pr.Head, rc.HeadRemote
+	}
+
+	// fork 的分支跟踪 fork 仓库地址，同名本地分支已跟踪别处时加上 fork 所有者前缀
+	branch := pr.Head
+	if git.BranchExists(branch) && git.GetConfig("branch."+branch+".remote") != pr.HeadCloneURL {
+		branch = git.SanitizeBranchName(pr.HeadOwner) + "-" + pr.Head
+	}
+	return branch, pr.HeadCloneURL
+}
+
+// prWorktreesDir returns the directory holding PR worktrees of the repository at root
+func prWorktreesDir(root string) string {
+	return filepath.Join(filepath.Dir(root), filepath.Base(root)+"-worktrees")
+}
+
+func prWorktreePath(root string, number int) string {
+	return filepath.Join(prWorktreesDir(root), fmt.Sprintf("pr-%d", number))
+}
+
+// cleanPRWorktrees removes worktrees created for PRs that are no longer open
+func cleanPRWorktrees(ghClient *github.Client) {
+	if err := git.PruneWorktrees(); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to prune worktrees: %v", err))
+	}
+
+	worktrees, err := git.ListWorktrees()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to list worktrees: %v", err))
+		return
+	}
+
+	owner, repo, err := github.GetCurrentRepository()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to get repository info: %v", err))
+		return
+	}
+
+	type staleWorktree struct {
+		git.Worktree
+		pr *github.PullRequest
+	}
+
+	dir := prWorktreesDir(worktrees[0].Path)
+	stale := make([]staleWorktree, 0)
+	for _, wt := range worktrees[1:] {
+		if filepath.Dir(wt.Path) != dir {
+			continue
+		}
+		number, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(wt.Path), "pr-"))
+		if err != nil {
+			continue
+		}
+
+		pr, err := ghClient.GetPullRequest(owner, repo, number)
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Failed to get PR #%d: %v", number, err))
+			continue
+		}
+		if pr.State != "open" {
+			stale = append(stale, staleWorktree{Worktree: wt, pr: pr})
+		}
+	}
+
+	if len(stale) == 0 {
+		ui.Success("No stale PR worktrees")
+		return
+	}
+
+	fmt.Println()
+	ui.Info("Worktrees of merged or closed PRs:")
+	for _, wt := range stale {
+		state := "closed"
+		if wt.pr.Merged {
+			state = "merged"
+		}
+		fmt.Printf("  %s  #%d %s (%s)\n", wt.Path, wt.pr.Number, wt.pr.Title, state)
+	}
+	fmt.Println()
+
+	if !checkoutYes {
+		ok, err := ui.PromptConfirm(fmt.Sprintf("Remove %d worktree(s)?", len(stale)), true)
+		if err != nil || !ok {
+			ui.Info("Clean cancelled")
+			return
+		}
+	}
+
+	for _, wt := range stale {
+		if !checkoutForce {
+			if dirty, err := git.HasUncommittedChangesIn(wt.Path); err == nil && dirty {
+				ui.Warning(fmt.Sprintf("Skipping %s: uncommitted changes (use --force to remove)", wt.Path))
+				continue
+			}
+		}
+
+		if err := git.RemoveWorktree(wt.Path, checkoutForce); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to remove worktree: %v", err))
+			continue
+		}
+		ui.Success(fmt.Sprintf("Removed %s", wt.Path))
+
+		// 已合并 PR 的本地分支不再需要
+		if wt.pr.Merged && wt.Branch != "" {
+			if err := git.DeleteBranch(wt.Branch); err != nil {
+				ui.Warning(fmt.Sprintf("Failed to delete local branch: %v", err))
+			} else {
+				ui.Success(fmt.Sprintf("Deleted local branch %s", wt.Branch))
+			}
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/jira"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/Wangggym/quick-workflow/internal/watcher"
+	"github.com/spf13/cobra"
+)
+
+var (
+	closeComment      string
+	closeDeleteBranch bool
+	closeJiraStatus   string
+	closeTransition   bool
+	reopenComment     string
+)
+
+var prCloseCmd = &cobra.Command{
+	Use:   "close [pr]",
+	Short: "Close a PR without merging",
+	Long: `Close a pull request without merging it and optionally:
+  - Leave a closing comment
+  - Delete the remote and local branch
+  - Transition the Jira ticket(s) back to another status
+
+The PR is removed from the watching list.
+
+Arguments:
+` + prRefHelp + `
+
+Examples:
+  qkflow pr close 123
+  qkflow pr close 123 -c "Superseded by #130" --delete-branch
+  qkflow pr close 123 --jira-status "To Do"
+  qkflow pr close 123 --transition-jira     # pick the Jira status interactively`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runPRClose,
+}
+
+var prReopenCmd = &cobra.Command{
+	Use:   "reopen [pr]",
+	Short: "Reopen a closed PR",
+	Long: `Reopen a closed (not merged) pull request and add it back to the watching list.
+
+Arguments:
+` + prRefHelp,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runPRReopen,
+}
+
+func init() {
+	prCloseCmd.Flags().StringVarP(&closeComment, "comment", "c", "", "Comment to add before closing")
+	prCloseCmd.Flags().BoolVarP(&closeDeleteBranch, "delete-branch", "d", false, "Delete the remote and local branch")
+	prCloseCmd.Flags().StringVar(&closeJiraStatus, "jira-status", "", "Transition linked Jira ticket(s) to this status")
+	prCloseCmd.Flags().BoolVar(&closeTransition, "transition-jira", false, "Select the Jira status to transition linked ticket(s) to")
+
+	prReopenCmd.Flags().StringVarP(&reopenComment, "comment", "c", "", "Comment to add after reopening")
+}
+
+func runPRClose(cmd *cobra.Command, args []string) {
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	resolved := resolvePRArg(ghClient, args, "close", false)
+	if resolved == nil {
+		return
+	}
+	owner, repo, pr := resolved.Owner, resolved.Repo, resolved.PR
+
+	if pr.State != "open" {
+		ui.Error(fmt.Sprintf("PR is not open (state: %s)", pr.State))
+		return
+	}
+
+	if closeComment != "" {
+		if err := ghClient.AddPRComment(owner, repo, pr.Number, closeComment); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to add comment: %v", err))
+		}
+	}
+
+	ui.Info(fmt.Sprintf("Closing PR #%d...", pr.Number))
+	if _, err := ghClient.ClosePullRequest(owner, repo, pr.Number); err != nil {
+		ui.Error(fmt.Sprintf("Failed to close PR: %v", err))
+		return
+	}
+	ui.Success("Pull request closed")
+
+	tickets := linkedJiraTickets(owner, repo, pr)
+
+	// 从 watching list 中移除（关闭的 PR 不会再被合并）
+	watchingList, err := watcher.NewWatchingList()
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to load watching list: %v", err))
+	} else if watchingList.Exists(owner, repo, pr.Number) {
+		if err := watchingList.Remove(owner, repo, pr.Number); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to remove PR from watching list: %v", err))
+		} else {
+			ui.Info("Removed PR from watching list")
+		}
+	}
+
+	if closeDeleteBranch {
+		headOwner, headRepo := pr.HeadRepository(owner, repo)
+		deletePRBranches(ghClient, headOwner, headRepo, pr.Head)
+	}
+
+	if closeJiraStatus != "" || closeTransition {
+		transitionJiraTickets(tickets, closeJiraStatus)
+	}
+
+	fmt.Println()
+	ui.Success("All done! 🎉")
+}
+
+func runPRReopen(cmd *cobra.Command, args []string) {
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	resolved := resolvePRArg(ghClient, args, "reopen", true)
+	if resolved == nil {
+		return
+	}
+	owner, repo, pr := resolved.Owner, resolved.Repo, resolved.PR
+
+	if pr.State == "open" {
+		ui.Info(fmt.Sprintf("PR #%d is already open", pr.Number))
+		return
+	}
+	if pr.Merged {
+		ui.Error("PR is already merged and cannot be reopened")
+		return
+	}
+
+	ui.Info(fmt.Sprintf("Reopening PR #%d...", pr.Number))
+	pr, err = ghClient.ReopenPullRequest(owner, repo, pr.Number)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to reopen PR: %v", err))
+		ui.Info("A PR cannot be reopened if its branch was deleted; push the branch again first")
+		return
+	}
+	ui.Success(fmt.Sprintf("Pull request reopened: %s", pr.HTMLURL))
+
+	if reopenComment != "" {
+		if err := ghClient.AddPRComment(owner, repo, pr.Number, reopenComment); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to add comment: %v", err))
+		}
+	}
+
+	watchingList, err := watcher.NewWatchingList()
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to load watching list: %v", err))
+		return
+	}
+
+	headOwner := ""
+	if pr.IsCrossRepository(owner, repo) {
+		headOwner = pr.HeadOwner
+	}
+
+	if err := watchingList.Add(watcher.WatchingPR{
+		PRNumber:    pr.Number,
+		Owner:       owner,
+		Repo:        repo,
+		Branch:      pr.Head,
+		HeadOwner:   headOwner,
+		Title:       pr.Title,
+		PRURL:       pr.HTMLURL,
+		JiraTickets: jira.ExtractIssueKeys(pr.Title, pr.Head),
+	}); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to add PR to watching list: %v", err))
+	} else {
+		ui.Info("✅ Added PR back to watching list for auto Jira updates")
+	}
+}
+
+// linkedJiraTickets returns the Jira tickets of a PR from the watching list, title and branch
+func linkedJiraTickets(owner, repo string, pr *github.PullRequest) []string {
+	texts := []string{pr.Title, pr.Head}
+
+	watchingList, err := watcher.NewWatchingList()
+	if err == nil {
+		if entry := watchingList.Get(owner, repo, pr.Number); entry != nil {
+			texts = append(entry.JiraTickets, texts...)
+		}
+	}
+
+	return jira.ExtractIssueKeys(texts...)
+}
+
+// transitionJiraTickets moves the tickets to status, asking for it when empty
+func transitionJiraTickets(tickets []string, status string) {
+	if len(tickets) == 0 {
+		ui.Info("No Jira ticket linked to this PR")
+		return
+	}
+
+	jiraClient, err := jira.NewClient()
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to create Jira client: %v", err))
+		return
+	}
+
+	for _, ticket := range tickets {
+		target := status
+		if target == "" {
+			statuses, err := jiraClient.GetProjectStatuses(jira.ExtractProjectKey(ticket))
+			if err != nil {
+				ui.Warning(fmt.Sprintf("Failed to get statuses: %v", err))
+				continue
+			}
+			target, err = ui.PromptSelect(fmt.Sprintf("New status for %s:", ticket), statuses)
+			if err != nil {
+				if err.Error() == "interrupt" {
+					ui.Warning("Operation cancelled by user")
+					os.Exit(0)
+				}
+				ui.Warning(fmt.Sprintf("Failed to select status: %v", err))
+				continue
+			}
+		}
+
+		ui.Info(fmt.Sprintf("Updating %s status to: %s", ticket, target))
+		if err := jiraClient.UpdateStatus(ticket, target); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to update status: %v", err))
+		} else {
+			ui.Success(fmt.Sprintf("Updated %s status to: %s", ticket, target))
+		}
+	}
+}
+
+// deletePRBranches deletes the PR head branch remotely, and the local branch
+// tracking it when it has no commits beyond the PR branch
+func deletePRBranches(ghClient *github.Client, owner, repo, branch string) {
+	ui.Info(fmt.Sprintf("Deleting remote branch %s...", branch))
+	if err := ghClient.DeleteBranch(owner, repo, branch); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to delete remote branch: %v (may already be deleted)", err))
+	} else {
+		ui.Success("Remote branch deleted")
+	}
+
+	if !git.IsGitRepository() {
+		return
+	}
+
+	// 只删除跟踪这个 PR 分支的本地分支，同名的其他分支可能是别人的或者有未推送的工作
+	local := trackingBranch(owner, repo, branch)
+	if local == "" {
+		ui.Info(fmt.Sprintf("No local branch tracks %s/%s:%s, keeping local branches", owner, repo, branch))
+		return
+	}
+
+	currentBranch, err := git.GetCurrentBranch()
+	if err == nil && currentBranch == local {
+		defaultBranch, err := git.GetDefaultBranch()
+		if err != nil {
+			defaultBranch = "main"
+		}
+		ui.Info(fmt.Sprintf("Switching to %s branch...", defaultBranch))
+		if err := git.CheckoutBranch(defaultBranch); err != nil {
+			ui.Warning(fmt.Sprintf("Could not switch to %s, keeping local branch %s", defaultBranch, local))
+			return
+		}
+	}
+
+	// -d 在分支有未推送的提交时拒绝删除
+	if err := git.DeleteMergedBranch(local); err != nil {
+		ui.Warning(fmt.Sprintf("Keeping local branch %s: it has commits that are not in the PR branch", local))
+		ui.Info(fmt.Sprintf("Delete it anyway with 'git branch -D %s'", local))
+	} else {
+		ui.Success(fmt.Sprintf("Local branch %s deleted", local))
+	}
+}
+
+// trackingBranch returns the local branch whose upstream is branch of the
+// GitHub repository owner/repo, or "" when there is none
+func trackingBranch(owner, repo, branch string) string {
+	branches, err := git.ListLocalBranches()
+	if err != nil {
+		ui.Warning(err.Error())
+		return ""
+	}
+
+	for _, b := range branches {
+		if b.UpstreamRemote == "" || b.UpstreamBranch != branch {
+			continue
+		}
+		remoteOwner, remoteRepo, err := github.RemoteRepository(b.UpstreamRemote)
+		if err == nil && strings.EqualFold(remoteOwner, owner) && strings.EqualFold(remoteRepo, repo) {
+			return b.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	conflictsBase   string
+	conflictsRebase bool
+	conflictsSign   string
+)
+
+var prConflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "Check whether the current branch conflicts with its base branch",
+	Long: `Fetch the base branch and do a trial merge of the current commit into it,
+without touching the working tree, the index or any branch. The files that
+would conflict are listed and you are offered to rebase onto the base branch.
+
+The base branch is taken from --base, the base of the branch's open PR, or the
+default branch of the repository.
+
+Examples:
+  qkflow pr conflicts                    # check against the PR's base
+  qkflow pr conflicts --base release/2.3 # check against another branch
+  qkflow pr conflicts --rebase           # rebase without asking on conflicts`,
+	Args: cobra.NoArgs,
+	Run:  runPRConflicts,
+}
+
+func init() {
+	prConflictsCmd.Flags().StringVar(&conflictsBase, "base", "", "Branch to check against (default: the PR's base, else the default branch)")
+	prConflictsCmd.Flags().BoolVar(&conflictsRebase, "rebase", false, "Rebase onto the base branch without asking when there are conflicts")
+	prConflictsCmd.Flags().StringVar(&conflictsSign, "sign", "", "Sign rebased commits with gpg or ssh (default: commit_signing from config, else git config)")
+}
+
+func runPRConflicts(cmd *cobra.Command, args []string) {
+	if !git.IsGitRepository() {
+		ui.Error("Not a git repository")
+		return
+	}
+
+	if git.RebaseInProgress() {
+		ui.Error("A rebase is in progress. Finish it with 'git rebase --continue' (or 'git rebase --abort') first")
+		return
+	}
+
+	signing, err := commitSigning(conflictsSign)
+	if err != nil {
+		ui.Error(err.Error())
+		return
+	}
+
+	branch, err := git.GetCurrentBranch()
+	if err != nil || branch == "" {
+		ui.Error("Not on a branch, check out the branch to check first")
+		return
+	}
+
+	rc, err := github.DetectRepoContext()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to get repository info: %v", err))
+		return
+	}
+
+	baseBranch := conflictsBase
+	if baseBranch == "" {
+		if ghClient, err := github.NewClient(); err == nil {
+			if pr, err := ghClient.GetPRByBranch(rc.BaseOwner, rc.BaseRepo, rc.HeadRef(branch)); err == nil && pr != nil {
+				baseBranch = pr.Base
+				ui.Info(fmt.Sprintf("PR #%d targets %s", pr.Number, pr.Base))
+			}
+		} else {
+			ui.Warning(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		}
+	}
+	if baseBranch == "" {
+		baseBranch, err = git.GetDefaultBranchOf(rc.BaseRemote)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to detect the default branch: %v", err))
+			return
+		}
+	}
+	if branch == baseBranch {
+		ui.Error(fmt.Sprintf("You are on %s itself, check out a feature branch to check", baseBranch))
+		return
+	}
+
+	ui.Info(fmt.Sprintf("Fetching %s/%s...", rc.BaseRemote, baseBranch))
+	if err := git.FetchBranch(rc.BaseRemote, baseBranch); err != nil {
+		ui.Error(fmt.Sprintf("Failed to fetch: %v", err))
+		return
+	}
+
+	base := rc.BaseRemote + "/" + baseBranch
+	if !checkBaseConflicts(base, signing, conflictsRebase, true) {
+		if git.RebaseInProgress() {
+			printSyncConflictHelp(false, "")
+		}
+		return
+	}
+}
+
+// checkBaseConflicts does a trial merge of HEAD into base and lists the files
+// that would conflict. It rebases onto base right away with rebase, offers to
+// with prompt, and otherwise only reports the conflicts. It returns false when
+// the rebase failed or was left in progress on conflicts; not rebasing returns true.
+func checkBaseConflicts(base string, signing git.Signing, rebase, prompt bool) bool {
+	files, err := git.MergeConflicts(base, "HEAD")
+	if err != nil {
+		// 旧版 git 不支持 merge-tree --write-tree，跳过检查
+		ui.Warning(fmt.Sprintf("Could not check for conflicts with %s: %v", base, err))
+		return true
+	}
+	if len(files) == 0 {
+		ui.Success(fmt.Sprintf("No conflicts with %s", base))
+		return true
+	}
+
+	ui.Warning(fmt.Sprintf("%d file(s) conflict with %s:", len(files), base))
+	for _, file := range files {
+		fmt.Printf("  %s\n", file)
+	}
+
+	if !rebase && !prompt {
+		ui.Warning("The PR will conflict with its base branch, rebase with 'qkflow pr conflicts --rebase'")
+		return true
+	}
+	if !rebase {
+		ok, err := ui.PromptConfirm(fmt.Sprintf("Rebase onto %s first?", base), true)
+		if err != nil {
+			if err.Error() == "interrupt" {
+				ui.Warning("Operation cancelled by user")
+				os.Exit(0)
+			}
+			return true
+		}
+		if !ok {
+			ui.Warning("Not rebasing, the PR will conflict with its base branch")
+			return true
+		}
+	}
+
+	// 改写前的提交，rebase 之后仍可找回
+	head, _ := git.RevParse("HEAD")
+
+	ui.Info(fmt.Sprintf("Rebasing onto %s...", base))
+	if err := git.RebaseAutostash(base, signing); err != nil {
+		ui.Error(err.Error())
+		return false
+	}
+	ui.Success(fmt.Sprintf("Rebased onto %s", base))
+	if head != "" {
+		ui.Info(fmt.Sprintf("The branch was at %s before the rebase", shortSHA(head)))
+	}
+	return true
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/Wangggym/quick-workflow/internal/git"
 	"github.com/Wangggym/quick-workflow/internal/github"
 	"github.com/Wangggym/quick-workflow/internal/jira"
+	"github.com/Wangggym/quick-workflow/internal/journal"
 	"github.com/Wangggym/quick-workflow/internal/ui"
 	"github.com/Wangggym/quick-workflow/internal/watcher"
 	"github.com/Wangggym/quick-workflow/pkg/config"
@@ -22,6 +23,20 @@ var (
 	prTypes  []string
 	noTicket bool
 	prTitle  string
+	prStack  bool
+	prFork   bool
+	prBase   string
+	prPaths  []string
+
+	prNoVerify  bool
+	prNoScan    bool
+	prNoChecks  bool
+	prSign      string
+	prScope     string
+	prCoAuthors []string
+
+	prContinue bool
+	prAbort    bool
 )
 
 var prCreateCmd = &cobra.Command{
@@ -33,7 +48,63 @@ var prCreateCmd = &cobra.Command{
   - Push to remote
   - Create a GitHub PR
   - Add PR link to Jira
-  - Update Jira status`,
+  - Update Jira status
+
+Use --stack to base the PR on the current branch instead of the default
+branch. The PR is recorded in the repository's stack, see 'qkflow stack'.
+
+Fork workflow: when an 'upstream' remote exists, origin is treated as your
+fork and the PR is opened against upstream. Without push access to origin
+(or with --fork) the repository is forked and added as the 'fork' remote.
+
+The base branch is taken from --base, a matching base_branch_rules entry
+(e.g. Bug tickets with fix version 2.3 -> release/2.3) or a picker over remote
+branches matching base_branch_patterns. The current commit must be based on it.
+
+Branch names follow the template, slug length, case and pattern in
+branch_naming of the config or 'branch' in the repository's .qkflow.yaml.
+
+Commit messages follow commit_message in the config or 'commit' in
+.qkflow.yaml: a template, Conventional Commits validation with allowed types
+and scopes (--scope), the Jira link in the body and Co-authored-by trailers
+(--co-author).
+
+By default a picker lists the changed files with their diff stats. Content you
+already staged with 'git add' is committed as is, and --paths stages only the
+matching changes. Untracked files that look like secrets, build outputs or large
+binaries are not selected unless you pick them.
+
+Without uncommitted changes, a PR is created from the commits on the current
+feature branch that are not in the base branch: branch creation and commit are
+skipped, the Jira ticket is taken from the branch name and the title and
+description from the commits.
+
+The pre-commit and commit-msg hooks run on commit; skip them with --no-verify.
+With pre_commit_check enabled in the config, the pre-commit hook also runs
+before the branch is created, so a failing check leaves nothing behind.
+
+Commits are signed with --sign gpg|ssh or commit_signing from the config.
+When branch protection requires signed commits, pr create stops before
+touching the repository if signing isn't set up, and before pushing if a
+commit isn't signed.
+
+Before pushing, the changes are scanned for credentials (AWS keys, GitHub
+tokens, private keys, the tokens of the qkflow config), .env files and large
+files. Findings stop the push, or only warn with push_scan.mode: warn; allow
+paths and patterns in push_scan or 'scan' in .qkflow.yaml, or skip the scan
+with --no-scan.
+
+Before pushing, the new commit is merged into the latest base branch as a
+trial, without touching the working tree. Files that would conflict are listed
+and you are offered to rebase first; 'qkflow pr conflicts' runs the same check.
+
+The checks declared under 'checks' in .qkflow.yaml (linters, unit tests,
+formatters) run on the committed changes before pushing, once you trusted
+them; a failing check stops the push. Skip them with --no-checks.
+
+Every step is recorded in a journal inside the .git directory. When a step
+fails or the command is interrupted, fix the problem and resume with
+--continue, or roll everything back with --abort.`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runPRCreate,
 }
@@ -43,6 +114,18 @@ func init() {
 	prCreateCmd.Flags().StringSliceVar(&prTypes, "types", []string{}, "Change types (e.g., feat,fix,docs)")
 	prCreateCmd.Flags().BoolVar(&noTicket, "no-ticket", false, "Skip Jira ticket (proceed without ticket)")
 	prCreateCmd.Flags().StringVar(&prTitle, "title", "", "PR title (if not provided, will be generated from description)")
+	prCreateCmd.Flags().BoolVar(&prStack, "stack", false, "Stack the PR on the current branch instead of the default branch")
+	prCreateCmd.Flags().BoolVar(&prFork, "fork", false, "Fork the repository and push the branch to the fork")
+	prCreateCmd.Flags().StringVar(&prBase, "base", "", "Base branch of the PR (default: rules/picker from config, else the default branch)")
+	prCreateCmd.Flags().StringSliceVar(&prPaths, "paths", []string{}, "Only commit changes under these paths (added to what is already staged)")
+	prCreateCmd.Flags().StringVar(&prScope, "scope", "", "Conventional Commits scope of the commit (e.g. auth)")
+	prCreateCmd.Flags().StringSliceVar(&prCoAuthors, "co-author", []string{}, "Add a Co-authored-by trailer, as \"Name <email>\" (repeatable)")
+	prCreateCmd.Flags().BoolVar(&prNoVerify, "no-verify", false, "Skip the pre-commit and commit-msg hooks")
+	prCreateCmd.Flags().BoolVar(&prNoScan, "no-scan", false, "Skip the scan for secrets and large files before pushing")
+	prCreateCmd.Flags().BoolVar(&prNoChecks, "no-checks", false, "Skip the checks of .qkflow.yaml before pushing")
+	prCreateCmd.Flags().StringVar(&prSign, "sign", "", "Sign the commit with gpg or ssh (default: commit_signing from config, else git config)")
+	prCreateCmd.Flags().BoolVar(&prContinue, "continue", false, "Resume an interrupted pr create")
+	prCreateCmd.Flags().BoolVar(&prAbort, "abort", false, "Roll back an interrupted pr create")
 }
 
 func runPRCreate(cmd *cobra.Command, args []string) {
@@ -52,22 +135,131 @@ func runPRCreate(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	signing, err := commitSigning(prSign)
+	if err != nil {
+		ui.Error(err.Error())
+		return
+	}
+
+	journalPath, err := prCreateJournalPath()
+	if err != nil {
+		ui.Error(err.Error())
+		return
+	}
+	j, err := journal.Load(journalPath)
+	if err != nil {
+		ui.Error(err.Error())
+		return
+	}
+
+	if prContinue || prAbort {
+		if j == nil {
+			ui.Error("No pr create in progress")
+			return
+		}
+		if prAbort {
+			abortPRCreate(j)
+			return
+		}
+		ui.Info(fmt.Sprintf("Resuming pr create of branch %s...", j.Branch))
+		if prNoVerify {
+			j.NoVerify = true
+		}
+		if prNoScan {
+			j.NoScan = true
+		}
+		if prNoChecks {
+			j.NoChecks = true
+		}
+		if prSign != "" {
+			j.Sign = prSign
+		}
+		executePRCreate(j)
+		return
+	}
+
+	if j != nil {
+		ui.Error(fmt.Sprintf("A pr create of branch %s is in progress (started %s)", j.Branch, j.StartedAt))
+		ui.Info("Resume it with 'qkflow pr create --continue' or roll it back with 'qkflow pr create --abort'")
+		return
+	}
+
 	// 记录原始分支，以便失败时回退
-	originalBranch, err := git.GetCurrentBranch()
+	originalBranch, err := repository.CurrentBranch()
 	if err != nil {
 		ui.Error(fmt.Sprintf("Failed to get current branch: %v", err))
 		return
 	}
 
+	// 确定 PR 目标仓库和推送仓库（fork 模式下两者不同）
+	rc, err := resolveRepoContext(prFork)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to determine repository: %v", err))
+		return
+	}
+
+	// 堆叠 PR：以当前分支为父分支
+	var stackParentSHA string
+	if prStack {
+		if rc.IsFork() {
+			ui.Error("--stack is not supported for PRs from a fork")
+			return
+		}
+		defaultBranch, _ := repository.DefaultBranch(rc.BaseRemote)
+		if originalBranch == "" || originalBranch == defaultBranch {
+			ui.Error("--stack needs to be run from the branch the new PR builds on, not the default branch")
+			return
+		}
+		stackParentSHA, err = git.RevParse(originalBranch)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to resolve %s: %v", originalBranch, err))
+			return
+		}
+	}
+
 	// 检查是否有未提交的更改
-	hasChanges, err := git.HasUncommittedChanges()
+	hasChanges, err := repository.HasUncommittedChanges()
 	if err != nil {
 		ui.Error(fmt.Sprintf("Failed to check git status: %v", err))
 		return
 	}
+
+	// 没有未提交的更改时，从已提交到当前功能分支的改动创建 PR
+	var commitSubjects []string
 	if !hasChanges {
-		ui.Error("No changes to commit. Please stage your changes first with 'git add'")
-		return
+		commitSubjects = committedWork(rc, originalBranch)
+		if len(commitSubjects) == 0 {
+			ui.Error("No changes to commit. Please stage your changes first with 'git add'")
+			ui.Info("Or run it on a feature branch with commits that are not in the base branch")
+			return
+		}
+		if prs, err := findBranchPRs(rc, originalBranch); err == nil && len(prs) > 0 {
+			ui.Error(fmt.Sprintf("Branch %s already has PR #%d: %s", originalBranch, prs[0].Number, prs[0].HTMLURL))
+			ui.Info("Use 'qkflow update' to push new commits to it")
+			return
+		}
+		ui.Info(fmt.Sprintf("Creating a PR from %d commit(s) on %s", len(commitSubjects), originalBranch))
+	}
+	fromCommits := len(commitSubjects) > 0
+
+	// 提供了 --types 或 --pr-desc 时视为非交互模式
+	interactive := len(prTypes) == 0 && prDesc == ""
+
+	// 选择要提交的文件
+	staging := &stagingPlan{}
+	if !fromCommits {
+		var ok bool
+		staging, ok = planStaging(prPaths, interactive, true)
+		if !ok {
+			return
+		}
+
+		// 创建分支前先运行 pre-commit 钩子，失败时不会留下半成品
+		if cfg := config.Get(); cfg != nil && cfg.PreCommitCheck && !prNoVerify {
+			if !runPreCommitCheck(*staging) {
+				return
+			}
+		}
 	}
 
 	// 获取或输入 Jira ticket
@@ -77,6 +269,10 @@ func runPRCreate(cmd *cobra.Command, args []string) {
 		jiraTicket = ""
 	} else if len(args) > 0 {
 		jiraTicket = args[0]
+	} else if keys := jira.ExtractIssueKeys(append([]string{originalBranch}, commitSubjects...)...); fromCommits && len(keys) > 0 {
+		// 分支名或提交信息中带有 Jira ticket
+		jiraTicket = keys[0]
+		ui.Info(fmt.Sprintf("Using Jira ticket %s from the branch", jiraTicket))
 	} else {
 		jiraTicket, err = ui.PromptInput("Jira ticket (optional, press Enter to skip):", false)
 		if err != nil {
@@ -217,6 +413,13 @@ func runPRCreate(cmd *cobra.Command, args []string) {
 				title = truncateString(titleText, 100)
 			}
 			ui.Success(fmt.Sprintf("Generated title: %s", title))
+		} else if fromCommits {
+			// 使用第一个提交的标题
+			title = titleFromCommit(commitSubjects[0])
+			if len(selectedTypes) > 0 {
+				title = ensureTitlePrefix(title, ui.ExtractPRType(selectedTypes[0]))
+			}
+			ui.Success(fmt.Sprintf("Generated title from commits: %s", title))
 		} else {
 			// 没有描述，手动输入
 			title, err = ui.PromptInput("Enter PR title:", true)
@@ -231,230 +434,543 @@ func runPRCreate(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	// 构建 PR body（包含描述）
-	prBody := buildPRBody(selectedTypes, jiraTicket, prDesc)
-
-	// 创建分支名
-	branchName := buildBranchName(jiraTicket, title)
-	cfg := config.Get()
-	if cfg.BranchPrefix != "" {
-		branchName = cfg.BranchPrefix + "/" + branchName
+	// 构建 PR body（包含描述），已有提交时默认列出提交
+	desc := prDesc
+	if desc == "" && fromCommits {
+		desc = commitListDescription(commitSubjects)
 	}
+	prBody := buildPRBody(selectedTypes, jiraTicket, desc)
 
-	ui.Info(fmt.Sprintf("Creating branch: %s", branchName))
+	// 确定目标分支：堆叠时为原分支，否则按 --base、规则或选择
+	var baseBranch string
+	if prStack {
+		if prBase != "" && prBase != originalBranch {
+			ui.Error("--base cannot be combined with --stack")
+			return
+		}
+		baseBranch = originalBranch
+	} else {
+		baseBranch, err = selectBaseBranch(rc.BaseRemote, prBase, jiraIssue)
+		if err != nil {
+			ui.Error(err.Error())
+			return
+		}
+		if fromCommits {
+			if !showCommittedWork(rc.BaseRemote, baseBranch) {
+				return
+			}
+		} else if !validateBaseBranch(rc.BaseRemote, baseBranch) {
+			ui.Info("PR creation cancelled")
+			return
+		}
+	}
+	ui.Info(fmt.Sprintf("Using base branch: %s", baseBranch))
 
-	// 创建分支
-	if err := git.CreateBranch(branchName); err != nil {
-		ui.Error(fmt.Sprintf("Failed to create branch: %v", err))
-		return
+	prType := ""
+	if len(selectedTypes) > 0 {
+		prType = ui.ExtractPRType(selectedTypes[0])
 	}
 
-	// Stage 所有更改
-	ui.Info("Staging changes...")
-	if err := git.AddAll(); err != nil {
-		ui.Error(fmt.Sprintf("Failed to stage changes: %v", err))
-		return
+	// 创建分支名和提交信息（已有提交时沿用当前分支和提交）
+	headBranch := originalBranch
+	var branchName, commitMessage string
+	if !fromCommits {
+		branchName, err = buildBranchName(jiraTicket, prType, title)
+		if err != nil {
+			ui.Error(err.Error())
+			ui.Info(fmt.Sprintf("Check branch_naming in the config or 'branch' in %s", config.RepoConfigFile))
+			return
+		}
+		headBranch = branchName
+
+		commitMessage, err = buildCommitMessage(jiraTicket, prType, title, interactive)
+		if err != nil {
+			ui.Error(err.Error())
+			ui.Info(fmt.Sprintf("Check commit_message in the config or 'commit' in %s", config.RepoConfigFile))
+			return
+		}
 	}
 
-	// 提交更改
-	commitMessage := title
+	// PR 标题
+	prTitleText := title
 	if jiraTicket != "" {
-		commitMessage = fmt.Sprintf("%s: %s", jiraTicket, title)
+		prTitleText = fmt.Sprintf("%s: %s", jiraTicket, title)
+	}
+
+	// 分支保护要求签名提交时，在改动仓库前确认提交会被签名
+	signRequired := false
+	if ghClient, err := github.NewClient(); err == nil {
+		signRequired = signingRequired(ghClient, rc.BaseOwner, rc.BaseRepo, baseBranch) ||
+			signingRequired(ghClient, rc.HeadOwner, rc.HeadRepo, headBranch)
+	}
+	if signRequired {
+		if fromCommits {
+			if !checkCommitsSigned(rc.BaseRemote + "/" + baseBranch) {
+				return
+			}
+		} else if !checkSigningSetup(baseBranch, signing) {
+			return
+		}
+	}
+
+	// 记录所有输入，之后每完成一步都写入 journal
+	j = journal.New(journalPath)
+	j.OriginalBranch = originalBranch
+	j.Branch = branchName
+	j.BaseBranch = baseBranch
+
+	if fromCommits {
+		// 已有的分支和提交直接使用，回滚时也不会删除
+		j.Branch = originalBranch
+		j.ExistingBranch = true
+		j.Steps = append(j.Steps, journal.StepBranch, journal.StepCommit)
+		j.StartSHA, err = git.RevParse("HEAD")
+		if err != nil {
+			ui.Error(err.Error())
+			return
+		}
+
+		remoteSHA, err := git.RemoteBranchSHA(rc.HeadRemote, originalBranch)
+		if err != nil {
+			ui.Warning(err.Error())
+		}
+		j.RemoteBranchExisted = remoteSHA != ""
+		if remoteSHA == j.StartSHA {
+			ui.Info(fmt.Sprintf("%s is already pushed to %s", originalBranch, rc.HeadRemote))
+			j.Steps = append(j.Steps, journal.StepPush)
+		}
 	} else {
-		// 无 Jira ticket 时，添加 # 前缀
-		commitMessage = fmt.Sprintf("# %s", title)
+		// 开始改动仓库前保存快照，回滚时据此精确恢复
+		snapshot, err := git.CreateSnapshot("pr create")
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to snapshot your changes: %v", err))
+			return
+		}
+		j.StartSHA = snapshot.Head
+		j.SnapshotRef = snapshot.Ref
 	}
 
-	ui.Info("Committing changes...")
-	if err := git.Commit(commitMessage); err != nil {
-		ui.Error(fmt.Sprintf("Failed to commit: %v", err))
+	j.BaseOwner, j.BaseRepo, j.BaseRemote = rc.BaseOwner, rc.BaseRepo, rc.BaseRemote
+	j.HeadOwner, j.HeadRepo, j.HeadRemote = rc.HeadOwner, rc.HeadRepo, rc.HeadRemote
+	j.JiraTicket = jiraTicket
+	j.Title = prTitleText
+	j.CommitMessage = commitMessage
+	j.Body = prBody
+	j.StagedOnly = staging.StagedOnly
+	j.StagePaths = staging.Pathspecs
+	j.NoVerify = prNoVerify
+	j.NoScan = prNoScan
+	j.NoChecks = prNoChecks
+	j.Interactive = interactive
+	j.Sign = prSign
+	j.SignRequired = signRequired
+	j.Stack = prStack
+	j.StackParentSHA = stackParentSHA
+	if err := j.Save(); err != nil {
+		ui.Error(err.Error())
 		return
 	}
 
-	// 推送分支
-	ui.Info("Pushing branch to remote...")
-	if err := git.Push(branchName); err != nil {
-		ui.Error(fmt.Sprintf("Failed to push: %v", err))
-		return
+	executePRCreate(j)
+}
+
+// executePRCreate runs the steps of pr create that the journal has not recorded yet
+func executePRCreate(j *journal.Journal) {
+	rc := &github.RepoContext{
+		BaseOwner:  j.BaseOwner,
+		BaseRepo:   j.BaseRepo,
+		BaseRemote: j.BaseRemote,
+		HeadOwner:  j.HeadOwner,
+		HeadRepo:   j.HeadRepo,
+		HeadRemote: j.HeadRemote,
 	}
+	// PR 创建在 base 仓库
+	owner, repo := rc.BaseOwner, rc.BaseRepo
 
-	// 获取仓库信息
-	remoteURL, err := git.GetRemoteURL()
-	if err != nil {
-		ui.Error(fmt.Sprintf("Failed to get remote URL: %v", err))
-		return
+	currentBranch, _ := repository.CurrentBranch()
+
+	// 创建分支
+	if !j.Done(journal.StepBranch) {
+		switch {
+		case currentBranch != j.Branch:
+			ui.Info(fmt.Sprintf("Creating branch: %s", j.Branch))
+			if err := repository.CreateBranch(j.Branch); err != nil {
+				ui.Error(fmt.Sprintf("Failed to create branch: %v", err))
+				// 还没有任何改动，不需要恢复
+				if j.SnapshotRef != "" {
+					git.DeleteSnapshot(j.SnapshotRef)
+				}
+				j.Remove()
+				return
+			}
+			j.BranchCreated = true
+		case currentBranch != j.OriginalBranch:
+			// 中断时分支已经创建
+			j.BranchCreated = true
+		default:
+			// 已经在同名的分支上，直接提交到这个分支
+			ui.Info(fmt.Sprintf("Already on branch %s, committing there", j.Branch))
+		}
+		markPRCreateStep(j, journal.StepBranch)
+	} else if currentBranch != j.Branch {
+		ui.Info(fmt.Sprintf("Switching to branch: %s", j.Branch))
+		if err := repository.CheckoutBranch(j.Branch); err != nil {
+			ui.Error(fmt.Sprintf("Failed to checkout branch: %v", err))
+			printPRCreateResumeHint()
+			return
+		}
 	}
 
-	owner, repo, err := github.ParseRepositoryFromURL(remoteURL)
-	if err != nil {
-		ui.Error(fmt.Sprintf("Failed to parse repository: %v", err))
-		return
+	// 提交更改
+	if !j.Done(journal.StepCommit) {
+		head, err := git.RevParse("HEAD")
+		if err != nil || head == j.StartSHA {
+			ui.Info("Staging changes...")
+			if err := applyStaging(stagingPlan{StagedOnly: j.StagedOnly, Pathspecs: j.StagePaths}); err != nil {
+				ui.Error(fmt.Sprintf("Failed to stage changes: %v", err))
+				printPRCreateResumeHint()
+				return
+			}
+
+			ui.Info("Committing changes...")
+			signing, err := commitSigning(j.Sign)
+			if err != nil {
+				ui.Error(err.Error())
+				printPRCreateResumeHint()
+				return
+			}
+			if err := repository.Commit(j.CommitMessage, git.CommitOptions{NoVerify: j.NoVerify, Signing: signing}); err != nil {
+				reportCommitError(err)
+				printPRCreateResumeHint()
+				return
+			}
+		}
+		markPRCreateStep(j, journal.StepCommit)
 	}
 
-	// 获取默认分支
-	defaultBranch, err := git.GetDefaultBranch()
-	if err != nil {
-		ui.Warning(fmt.Sprintf("Failed to detect default branch, using 'main': %v", err))
-		defaultBranch = "main"
+	// 推送分支
+	if !j.Done(journal.StepPush) {
+		if git.RebaseInProgress() {
+			ui.Error("A rebase is in progress. Finish it with 'git rebase --continue' (or 'git rebase --abort') first")
+			printPRCreateResumeHint()
+			return
+		}
+
+		// 在最新的 base 分支上试合并，提前发现冲突
+		ui.Info(fmt.Sprintf("Checking for conflicts with %s/%s...", j.BaseRemote, j.BaseBranch))
+		if err := git.FetchBranch(j.BaseRemote, j.BaseBranch); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to fetch %s/%s: %v", j.BaseRemote, j.BaseBranch, err))
+		} else {
+			signing, err := commitSigning(j.Sign)
+			if err != nil {
+				ui.Error(err.Error())
+				printPRCreateResumeHint()
+				return
+			}
+			// 已有的分支不是 pr create 创建的，--abort 无法撤销对它的 rebase，只报告冲突
+			prompt := j.Interactive && !j.ExistingBranch
+			preRebase, _ := git.RevParse("HEAD")
+			ok := checkBaseConflicts(j.BaseRemote+"/"+j.BaseBranch, signing, false, prompt)
+			// 记录 rebase 前的 HEAD，只有 rebase 改写的历史才能强推
+			if head, _ := git.RevParse("HEAD"); j.RebasedFrom == "" && (head != preRebase || git.RebaseInProgress()) {
+				j.RebasedFrom = preRebase
+				if err := j.Save(); err != nil {
+					ui.Warning(fmt.Sprintf("Failed to save progress: %v", err))
+				}
+			}
+			if !ok {
+				if git.RebaseInProgress() {
+					printSyncConflictHelp(false, "Run 'qkflow pr create --continue'")
+				}
+				printPRCreateResumeHint()
+				return
+			}
+		}
+
+		// 未签名的提交会被分支保护拒绝
+		if j.SignRequired && !checkCommitsSigned(j.BaseRemote+"/"+j.BaseBranch) {
+			printPRCreateResumeHint()
+			return
+		}
+		if !scanBeforePush(j.BaseRemote+"/"+j.BaseBranch, j.NoScan) {
+			printPRCreateResumeHint()
+			return
+		}
+		if !runRepoChecks(j.BaseRemote+"/"+j.BaseBranch, j.NoChecks) {
+			printPRCreateResumeHint()
+			return
+		}
+		// 已推送过的分支 rebase 之后需要 --force-with-lease
+		remoteSHA := ""
+		if j.RemoteBranchExisted {
+			remoteSHA, _ = git.RemoteBranchSHA(rc.HeadRemote, j.Branch)
+		}
+		if !pushBranch(rc.HeadRemote, j.Branch, remoteSHA, j.RebasedFrom) {
+			printPRCreateResumeHint()
+			return
+		}
+		markPRCreateStep(j, journal.StepPush)
 	}
-	ui.Info(fmt.Sprintf("Using base branch: %s", defaultBranch))
 
-	// 创建 PR
-	ui.Info("Creating pull request...")
 	ghClient, err := github.NewClient()
 	if err != nil {
 		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
-		rollbackBranch(originalBranch, branchName)
+		printPRCreateResumeHint()
 		return
 	}
 
-	pr, err := ghClient.CreatePullRequest(github.CreatePullRequestInput{
-		Owner: owner,
-		Repo:  repo,
-		Title: commitMessage,
-		Body:  prBody,
-		Head:  branchName,
-		Base:  defaultBranch,
-	})
-	if err != nil {
-		// 重试一次
-		ui.Warning(fmt.Sprintf("Failed to create PR: %v", err))
-		ui.Info("Retrying in 3 seconds...")
-		time.Sleep(3 * time.Second)
-
-		ui.Info("Retrying to create pull request...")
-		pr, err = ghClient.CreatePullRequest(github.CreatePullRequestInput{
-			Owner: owner,
-			Repo:  repo,
-			Title: commitMessage,
-			Body:  prBody,
-			Head:  branchName,
-			Base:  defaultBranch,
-		})
+	// 创建 PR
+	pr := &github.PullRequest{Number: j.PRNumber, HTMLURL: j.PRURL}
+	if !j.Done(journal.StepPR) {
+		pr, err = createPRWithRetry(ghClient, rc, j)
 		if err != nil {
 			ui.Error(fmt.Sprintf("Retry failed: %v", err))
-			ui.Info("Rolling back changes...")
-			rollbackBranch(originalBranch, branchName)
+			printPRCreateResumeHint()
 			return
 		}
+		ui.Success(fmt.Sprintf("Pull request created: %s", pr.HTMLURL))
+
+		j.PRNumber, j.PRURL = pr.Number, pr.HTMLURL
+		markPRCreateStep(j, journal.StepPR)
 	}
 
-	ui.Success(fmt.Sprintf("Pull request created: %s", pr.HTMLURL))
+	if j.Stack && !j.Done(journal.StepStack) {
+		registerStackBranch(ghClient, owner, repo, j.Branch, j.OriginalBranch, j.StackParentSHA, pr.Number)
+		markPRCreateStep(j, journal.StepStack)
+	}
 
 	// 更新 Jira
-	if jiraTicket != "" && jira.ValidateIssueKey(jiraTicket) {
-		jiraClient, err := jira.NewClient()
+	if j.JiraTicket != "" && !j.Done(journal.StepJira) {
+		updateJiraForNewPR(j.JiraTicket, pr.HTMLURL)
+		markPRCreateStep(j, journal.StepJira)
+	}
+
+	// 添加到 watching list
+	if !j.Done(journal.StepWatching) {
+		watchingList, err := watcher.NewWatchingList()
 		if err != nil {
-			ui.Warning(fmt.Sprintf("Failed to create Jira client: %v", err))
+			ui.Warning(fmt.Sprintf("Failed to load watching list: %v", err))
 		} else {
-			// 分配给当前用户
-			ui.Info("Assigning Jira ticket to you...")
-			if err := jiraClient.AssignToMe(jiraTicket); err != nil {
-				ui.Warning(fmt.Sprintf("Failed to assign ticket: %v", err))
-			} else {
-				ui.Success("Assigned Jira ticket to you")
+			// Extract Jira tickets
+			jiraTickets := make([]string, 0)
+			if j.JiraTicket != "" {
+				jiraTickets = append(jiraTickets, j.JiraTicket)
+			}
+
+			headOwner := ""
+			if rc.IsFork() {
+				headOwner = rc.HeadOwner
 			}
 
-			// 添加 PR 链接
-			ui.Info("Adding PR link to Jira...")
-			if err := jiraClient.AddPRLink(jiraTicket, pr.HTMLURL); err != nil {
-				ui.Warning(fmt.Sprintf("Failed to add PR link to Jira: %v", err))
+			watchingPR := watcher.WatchingPR{
+				PRNumber:    pr.Number,
+				Owner:       owner,
+				Repo:        repo,
+				Branch:      j.Branch,
+				HeadOwner:   headOwner,
+				Title:       j.Title,
+				PRURL:       pr.HTMLURL,
+				JiraTickets: jiraTickets,
+			}
+
+			if err := watchingList.Add(watchingPR); err != nil {
+				ui.Warning(fmt.Sprintf("Failed to add PR to watching list: %v", err))
 			} else {
-				ui.Success("Added PR link to Jira")
+				ui.Info("✅ Added PR to watching list for auto Jira updates")
 			}
+		}
+		markPRCreateStep(j, journal.StepWatching)
+	}
 
-			// 更新状态
-			projectKey := jira.ExtractProjectKey(jiraTicket)
+	if err := j.Remove(); err != nil {
+		ui.Warning(err.Error())
+	}
+	// 改动已提交并推送，不再需要快照
+	if j.SnapshotRef != "" {
+		git.DeleteSnapshot(j.SnapshotRef)
+	}
 
-			// 检查状态缓存
-			statusCache, err := jira.NewStatusCache()
-			if err != nil {
-				ui.Warning(fmt.Sprintf("Failed to create status cache: %v", err))
+	// 复制 URL 到剪贴板
+	copyToClipboard(pr.HTMLURL)
+
+	// 打开浏览器
+	openBrowser(pr.HTMLURL)
+
+	fmt.Println()
+	ui.Success("All done! 🎉")
+}
+
+// createPRWithRetry opens the PR of the journal, retrying once. A PR already
+// opened for the branch before an interruption is reused.
+func createPRWithRetry(ghClient *github.Client, rc *github.RepoContext, j *journal.Journal) (*github.PullRequest, error) {
+	head := rc.HeadRef(j.Branch)
+	if existing, err := ghClient.FindPRsByBranch(rc.BaseOwner, rc.BaseRepo, head, "open"); err == nil && len(existing) > 0 {
+		ui.Info(fmt.Sprintf("Found existing PR #%d for %s", existing[0].Number, j.Branch))
+		return &existing[0], nil
+	}
+
+	input := github.CreatePullRequestInput{
+		Owner: rc.BaseOwner,
+		Repo:  rc.BaseRepo,
+		Title: j.Title,
+		Body:  j.Body,
+		Head:  head,
+		Base:  j.BaseBranch,
+	}
+
+	ui.Info("Creating pull request...")
+	pr, err := ghClient.CreatePullRequest(input)
+	if err == nil {
+		return pr, nil
+	}
+
+	// 重试一次
+	ui.Warning(fmt.Sprintf("Failed to create PR: %v", err))
+	ui.Info("Retrying in 3 seconds...")
+	time.Sleep(3 * time.Second)
+
+	ui.Info("Retrying to create pull request...")
+	return ghClient.CreatePullRequest(input)
+}
+
+// markPRCreateStep records a completed step, warning when the journal can't be saved
+func markPRCreateStep(j *journal.Journal, step journal.Step) {
+	if err := j.Mark(step); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to save progress: %v", err))
+	}
+}
+
+func printPRCreateResumeHint() {
+	ui.Info("Progress is saved. Fix the problem and run 'qkflow pr create --continue' to resume,")
+	ui.Info("or 'qkflow pr create --abort' to roll back")
+}
+
+// updateJiraForNewPR assigns the ticket, links the PR and moves the ticket to the PR created status
+func updateJiraForNewPR(jiraTicket, prURL string) {
+	if !jira.ValidateIssueKey(jiraTicket) {
+		return
+	}
+
+	jiraClient, err := jira.NewClient()
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to create Jira client: %v", err))
+		return
+	}
+
+	// 分配给当前用户
+	ui.Info("Assigning Jira ticket to you...")
+	if err := jiraClient.AssignToMe(jiraTicket); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to assign ticket: %v", err))
+	} else {
+		ui.Success("Assigned Jira ticket to you")
+	}
+
+	// 添加 PR 链接
+	ui.Info("Adding PR link to Jira...")
+	if err := jiraClient.AddPRLink(jiraTicket, prURL); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to add PR link to Jira: %v", err))
+	} else {
+		ui.Success("Added PR link to Jira")
+	}
+
+	// 更新状态
+	projectKey := jira.ExtractProjectKey(jiraTicket)
+
+	// 检查状态缓存
+	statusCache, err := jira.NewStatusCache()
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to create status cache: %v", err))
+	} else {
+		mapping, err := statusCache.GetProjectStatus(projectKey)
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Failed to get cached status: %v", err))
+		} else if mapping == nil {
+			// 第一次使用，需要配置状态映射
+			// 检查是否有提供 --types 或 --pr-desc（表示可能是非交互模式）
+			if len(prTypes) > 0 || prDesc != "" {
+				ui.Error(fmt.Sprintf("❌ No status mapping found for project %s", projectKey))
+				ui.Info("💡 Please run 'qkflow pr create' interactively first to configure status mappings")
+				ui.Info("   Then you can use --types and --pr-desc flags for automation")
 			} else {
-				mapping, err := statusCache.GetProjectStatus(projectKey)
+				// 交互式配置状态映射
+				ui.Info(fmt.Sprintf("First time using project %s, please configure status mappings", projectKey))
+				mapping, err = setupProjectStatusMapping(jiraClient, projectKey)
 				if err != nil {
-					ui.Warning(fmt.Sprintf("Failed to get cached status: %v", err))
-				} else if mapping == nil {
-					// 第一次使用，需要配置状态映射
-					// 检查是否有提供 --types 或 --pr-desc（表示可能是非交互模式）
-					if len(prTypes) > 0 || prDesc != "" {
-						ui.Error(fmt.Sprintf("❌ No status mapping found for project %s", projectKey))
-						ui.Info("💡 Please run 'qkflow pr create' interactively first to configure status mappings")
-						ui.Info("   Then you can use --types and --pr-desc flags for automation")
+					ui.Warning(fmt.Sprintf("Failed to setup status mapping: %v", err))
+				} else if mapping != nil {
+					// 保存配置
+					if err := statusCache.SaveProjectStatus(mapping); err != nil {
+						ui.Warning(fmt.Sprintf("Failed to save status mapping: %v", err))
 					} else {
-						// 交互式配置状态映射
-						ui.Info(fmt.Sprintf("First time using project %s, please configure status mappings", projectKey))
-						mapping, err = setupProjectStatusMapping(jiraClient, projectKey)
-						if err != nil {
-							ui.Warning(fmt.Sprintf("Failed to setup status mapping: %v", err))
-						} else if mapping != nil {
-							// 保存配置
-							if err := statusCache.SaveProjectStatus(mapping); err != nil {
-								ui.Warning(fmt.Sprintf("Failed to save status mapping: %v", err))
-							} else {
-								ui.Success("Status mapping saved!")
-							}
-						}
+						ui.Success("Status mapping saved!")
 					}
 				}
+			}
+		}
 
-				// 使用缓存的状态更新
-				if mapping != nil && mapping.PRCreatedStatus != "" {
-					ui.Info(fmt.Sprintf("Updating Jira status to: %s", mapping.PRCreatedStatus))
-					if err := jiraClient.UpdateStatus(jiraTicket, mapping.PRCreatedStatus); err != nil {
-						ui.Warning(fmt.Sprintf("Failed to update status: %v", err))
-					} else {
-						ui.Success(fmt.Sprintf("Updated Jira status to: %s", mapping.PRCreatedStatus))
-					}
-				}
+		// 使用缓存的状态更新
+		if mapping != nil && mapping.PRCreatedStatus != "" {
+			ui.Info(fmt.Sprintf("Updating Jira status to: %s", mapping.PRCreatedStatus))
+			if err := jiraClient.UpdateStatus(jiraTicket, mapping.PRCreatedStatus); err != nil {
+				ui.Warning(fmt.Sprintf("Failed to update status: %v", err))
+			} else {
+				ui.Success(fmt.Sprintf("Updated Jira status to: %s", mapping.PRCreatedStatus))
 			}
 		}
 	}
+}
 
-	// 添加到 watching list
-	watchingList, err := watcher.NewWatchingList()
+// loadRepoConfig reads the .qkflow.yaml of the current repository
+func loadRepoConfig() (*config.RepoConfig, error) {
+	top, err := git.TopLevel()
 	if err != nil {
-		ui.Warning(fmt.Sprintf("Failed to load watching list: %v", err))
-	} else {
-		// Extract Jira tickets
-		jiraTickets := make([]string, 0)
-		if jiraTicket != "" {
-			jiraTickets = append(jiraTickets, jiraTicket)
-		}
-
-		watchingPR := watcher.WatchingPR{
-			PRNumber:    pr.Number,
-			Owner:       owner,
-			Repo:        repo,
-			Branch:      branchName,
-			Title:       commitMessage,
-			PRURL:       pr.HTMLURL,
-			JiraTickets: jiraTickets,
-		}
-
-		if err := watchingList.Add(watchingPR); err != nil {
-			ui.Warning(fmt.Sprintf("Failed to add PR to watching list: %v", err))
-		} else {
-			ui.Info("✅ Added PR to watching list for auto Jira updates")
-		}
+		return nil, err
 	}
+	return config.LoadRepo(top)
+}
 
-	// 复制 URL 到剪贴板
-	copyToClipboard(pr.HTMLURL)
+// buildBranchName names the new branch with branch_naming from the config,
+// overridden by the repository's .qkflow.yaml, and validates the result
+func buildBranchName(jiraTicket, prType, title string) (string, error) {
+	repo, err := loadRepoConfig()
+	if err != nil {
+		return "", err
+	}
+	naming := config.BranchNamingFor(repo)
+
+	data := git.BranchNameData{
+		Ticket: jiraTicket,
+		Type:   prType,
+		Slug:   git.BranchSlug(title, prType, naming.SlugMaxLength, naming.Lowercase),
+		User:   branchUser(),
+		Date:   time.Now().Format("20060102"),
+	}
+	name, err := git.RenderBranchName(naming.Template, data)
+	if err != nil {
+		return "", err
+	}
 
-	// 打开浏览器
-	openBrowser(pr.HTMLURL)
+	// branch_prefix 只用于默认模板，自定义模板自己包含前缀
+	if cfg := config.Get(); naming.Template == "" && cfg != nil && cfg.BranchPrefix != "" {
+		name = cfg.BranchPrefix + "/" + name
+	}
 
-	fmt.Println()
-	ui.Success("All done! 🎉")
+	if err := git.ValidateBranchName(name, naming.Pattern); err != nil {
+		return "", err
+	}
+	return name, nil
 }
 
-func buildBranchName(jiraTicket, title string) string {
-	sanitized := git.SanitizeBranchName(title)
-	if jiraTicket != "" {
-		return fmt.Sprintf("%s--%s", jiraTicket, sanitized)
+// branchUser returns the user name for branch names, taken from the git or qkflow email
+func branchUser() string {
+	email := git.GetConfig("user.email")
+	if email == "" {
+		if cfg := config.Get(); cfg != nil {
+			email = cfg.Email
+		}
 	}
-	return sanitized
+	user, _, _ := strings.Cut(email, "@")
+	return strings.ToLower(git.SanitizeBranchName(user))
 }
 
 func buildPRBody(types []string, jiraTicket string, prDesc string) string {
@@ -601,57 +1117,3 @@ func openBrowser(url string) {
 	}
 }
 
-// rollbackBranch rolls back branch creation when PR creation fails
-// It preserves changes, deletes the remote/local branch, and switches back to original branch
-func rollbackBranch(originalBranch, newBranch string) {
-	// 1. 撤销 commit 但保留改动在暂存区
-	ui.Info("Undoing commit to preserve changes...")
-	resetCmd := exec.Command("git", "reset", "--soft", "HEAD~1")
-	if err := resetCmd.Run(); err != nil {
-		ui.Warning(fmt.Sprintf("Failed to reset commit: %v", err))
-	}
-
-	// 2. 暂存改动
-	ui.Info("Stashing changes...")
-	stashCmd := exec.Command("git", "stash", "--include-untracked")
-	if err := stashCmd.Run(); err != nil {
-		ui.Warning(fmt.Sprintf("Failed to stash changes: %v", err))
-	}
-
-	// 3. 删除远程分支
-	ui.Info("Deleting remote branch...")
-	if err := git.DeleteRemoteBranch(newBranch); err != nil {
-		ui.Warning(fmt.Sprintf("Failed to delete remote branch: %v", err))
-	} else {
-		ui.Success("Remote branch deleted")
-	}
-
-	// 4. 切换回原分支
-	ui.Info(fmt.Sprintf("Switching back to branch: %s", originalBranch))
-	if err := git.CheckoutBranch(originalBranch); err != nil {
-		ui.Error(fmt.Sprintf("Failed to checkout original branch: %v", err))
-		ui.Warning("Your changes are in git stash. Use 'git stash pop' to recover them.")
-		return
-	}
-	ui.Success(fmt.Sprintf("Switched to branch: %s", originalBranch))
-
-	// 5. 删除本地新分支
-	ui.Info("Deleting local branch...")
-	if err := git.DeleteBranch(newBranch); err != nil {
-		ui.Warning(fmt.Sprintf("Failed to delete local branch: %v", err))
-	} else {
-		ui.Success("Local branch deleted")
-	}
-
-	// 6. 恢复暂存的改动
-	ui.Info("Restoring your changes...")
-	stashPopCmd := exec.Command("git", "stash", "pop")
-	if err := stashPopCmd.Run(); err != nil {
-		ui.Warning(fmt.Sprintf("Failed to restore changes: %v", err))
-		ui.Warning("Your changes are in git stash. Use 'git stash pop' to recover them.")
-	} else {
-		ui.Success("Changes restored to staging area")
-	}
-
-	ui.Success("Rollback completed! Your changes are preserved.")
-}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/jira"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+)
+
+// committedWork returns the subjects of the commits on branch that are not in
+// the base branch (--base or the default branch), oldest first. It returns nil
+// when branch is not a feature branch.
+func committedWork(rc *github.RepoContext, branch string) []string {
+	if prStack || branch == "" {
+		return nil
+	}
+
+	defaultBranch, err := repository.DefaultBranch(rc.BaseRemote)
+	if err != nil || branch == defaultBranch {
+		return nil
+	}
+
+	base := prBase
+	if base == "" {
+		base = defaultBranch
+	}
+	if branch == base {
+		return nil
+	}
+
+	if err := git.FetchBranch(rc.BaseRemote, base); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to fetch %s/%s: %v", rc.BaseRemote, base, err))
+	}
+
+	subjects, err := git.CommitSubjects(rc.BaseRemote+"/"+base, "HEAD")
+	if err != nil {
+		return nil
+	}
+	return subjects
+}
+
+// findBranchPRs returns the open PRs of a branch pushed to the head repository
+func findBranchPRs(rc *github.RepoContext, branch string) ([]github.PullRequest, error) {
+	ghClient, err := github.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return ghClient.FindPRsByBranch(rc.BaseOwner, rc.BaseRepo, rc.HeadRef(branch), "open")
+}
+
+// showCommittedWork lists the commits the PR will contain against base. It
+// returns false when there are none.
+func showCommittedWork(remote, base string) bool {
+	if err := git.FetchBranch(remote, base); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to fetch %s/%s: %v", remote, base, err))
+	}
+
+	commits, err := git.CommitsBetween(remote+"/"+base, "HEAD")
+	if err != nil {
+		ui.Error(err.Error())
+		return false
+	}
+	if len(commits) == 0 {
+		ui.Error(fmt.Sprintf("The current branch has no commits that are not in %s/%s", remote, base))
+		return false
+	}
+
+	ui.Info(fmt.Sprintf("The PR will include %d commit(s):", len(commits)))
+	for i, c := range commits {
+		if i == 10 {
+			fmt.Printf("  ... and %d more\n", len(commits)-10)
+			break
+		}
+		fmt.Printf("  %s\n", c)
+	}
+	return true
+}
+
+// titleFromCommit turns a commit subject into a PR title, dropping the
+// "TICKET: " or "# " prefix added by qkflow
+func titleFromCommit(subject string) string {
+	title := strings.TrimSpace(strings.TrimPrefix(subject, "# "))
+
+	if key, rest, ok := strings.Cut(title, ":"); ok {
+		key = strings.TrimSpace(key)
+		if keys := jira.ExtractIssueKeys(key); len(keys) == 1 && keys[0] == key {
+			title = strings.TrimSpace(rest)
+		}
+	}
+	return title
+}
+
+// commitListDescription builds the PR description listing the commit subjects
+func commitListDescription(subjects []string) string {
+	var desc strings.Builder
+	desc.WriteString("## Commits\n\n")
+	for _, subject := range subjects {
+		desc.WriteString(fmt.Sprintf("- %s\n", subject))
+	}
+	return desc.String()
+}
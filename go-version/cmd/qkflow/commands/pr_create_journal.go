@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/journal"
+	"github.com/Wangggym/quick-workflow/internal/stack"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/Wangggym/quick-workflow/internal/watcher"
+)
+
+// prCreateJournalPath returns where the pr create journal of the current worktree is kept
+func prCreateJournalPath() (string, error) {
+	return git.GitPath("qkflow/pr-create.json")
+}
+
+// abortPRCreate undoes the steps recorded in the journal, newest first
+func abortPRCreate(j *journal.Journal) {
+	ui.Info(fmt.Sprintf("Rolling back pr create of branch %s...", j.Branch))
+
+	if j.Done(journal.StepJira) {
+		ui.Warning(fmt.Sprintf("Jira ticket %s was already updated, revert its status manually if needed", j.JiraTicket))
+	}
+
+	if j.Done(journal.StepWatching) {
+		if watchingList, err := watcher.NewWatchingList(); err == nil && watchingList.Exists(j.BaseOwner, j.BaseRepo, j.PRNumber) {
+			if err := watchingList.Remove(j.BaseOwner, j.BaseRepo, j.PRNumber); err != nil {
+				ui.Warning(fmt.Sprintf("Failed to remove PR from watching list: %v", err))
+			}
+		}
+	}
+
+	if j.Done(journal.StepStack) {
+		if st, err := stack.Load(j.BaseOwner, j.BaseRepo); err == nil && st.Get(j.Branch) != nil {
+			st.Remove(j.Branch)
+			if err := st.Save(); err != nil {
+				ui.Warning(fmt.Sprintf("Failed to save stack: %v", err))
+			}
+		}
+	}
+
+	if j.PRNumber != 0 {
+		ui.Info(fmt.Sprintf("Closing PR #%d...", j.PRNumber))
+		ghClient, err := github.NewClient()
+		if err == nil {
+			_, err = ghClient.ClosePullRequest(j.BaseOwner, j.BaseRepo, j.PRNumber)
+		}
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Failed to close PR: %v", err))
+		} else {
+			ui.Success("Pull request closed")
+		}
+	}
+
+	if j.Done(journal.StepPush) && !j.RemoteBranchExisted {
+		ui.Info("Deleting remote branch...")
+		if err := git.DeleteRemoteBranchFrom(j.HeadRemote, j.Branch); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to delete remote branch: %v", err))
+		} else {
+			ui.Success("Remote branch deleted")
+		}
+	}
+
+	// 已有的分支保持原样
+	if !j.ExistingBranch && !rollbackPRCreateBranch(j) {
+		return
+	}
+
+	if err := j.Remove(); err != nil {
+		ui.Warning(err.Error())
+	}
+	ui.Success("Rollback completed! Your changes are preserved.")
+	if j.SnapshotRef != "" {
+		ui.Info(fmt.Sprintf("The snapshot is kept as %s, see 'qkflow recover --list'", strings.TrimPrefix(j.SnapshotRef, git.SnapshotRefPrefix)))
+	}
+}
+
+// rollbackPRCreateBranch removes the new branch and restores HEAD, the index
+// and the working tree from the snapshot taken before pr create started. Changes
+// made since then are saved in a new snapshot first, so no work is lost. It
+// returns false when the rollback stopped and needs manual recovery.
+func rollbackPRCreateBranch(j *journal.Journal) bool {
+	snapshot, err := git.LoadSnapshot(j.SnapshotRef)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to load the snapshot taken before pr create: %v", err))
+		return false
+	}
+
+	currentBranch, _ := repository.CurrentBranch()
+	head, _ := git.RevParse("HEAD")
+	branchCreated := j.BranchCreated && repository.BranchExists(j.Branch)
+
+	// 本地还没有任何改动时不需要恢复
+	if !branchCreated && currentBranch == j.OriginalBranch && head == j.StartSHA {
+		return true
+	}
+
+	// 中断后又做的改动也先保存下来
+	if dirty, _ := repository.HasUncommittedChanges(); dirty {
+		extra, err := git.CreateSnapshot(fmt.Sprintf("pr create --abort on %s", currentBranch))
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to save current changes: %v", err))
+			return false
+		}
+		ui.Info(fmt.Sprintf("Saved current changes as %s", extra.Name()))
+	}
+
+	// 原来处于 detached HEAD 时回到原提交
+	target := j.OriginalBranch
+	if target == "" {
+		target = j.StartSHA
+	}
+
+	ui.Info(fmt.Sprintf("Switching back to: %s", target))
+	if err := git.ForceCheckout(target); err != nil {
+		ui.Error(err.Error())
+		ui.Info(fmt.Sprintf("Restore your changes later with 'qkflow recover %s'", snapshot.Name()))
+		return false
+	}
+
+	// 提交在原来的分支上时，分支回到开始前的提交
+	if !j.BranchCreated && j.Branch == j.OriginalBranch {
+		if err := git.ResetSoft(j.StartSHA); err != nil {
+			ui.Error(err.Error())
+			ui.Info(fmt.Sprintf("Restore your changes later with 'qkflow recover %s'", snapshot.Name()))
+			return false
+		}
+	}
+
+	ui.Info("Restoring your changes...")
+	if err := snapshot.Restore(); err != nil {
+		ui.Error(err.Error())
+		ui.Info(fmt.Sprintf("Restore your changes later with 'qkflow recover %s'", snapshot.Name()))
+		return false
+	}
+	ui.Success("Changes restored exactly as before (staged, unstaged and untracked)")
+
+	if branchCreated {
+		ui.Info("Deleting local branch...")
+		if err := git.DeleteBranch(j.Branch); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to delete local branch: %v", err))
+		} else {
+			ui.Success("Local branch deleted")
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/git/testrepo"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+)
+
+// chdirRepoWithConfig changes into a temporary repository whose .qkflow.yaml has the given content
+func chdirRepoWithConfig(t *testing.T, repoConfig string) {
+	t.Helper()
+	dir := testrepo.New(t)
+	testrepo.WriteFile(t, dir, ".qkflow.yaml", repoConfig)
+	testrepo.Chdir(t, dir)
+}
+
+func TestBuildBranchNameForEveryPRType(t *testing.T) {
+	chdirRepoWithConfig(t, "branch:\n  template: \"{{.Type}}/{{.Slug}}\"\n  lowercase: true\n")
+
+	for _, option := range ui.PRTypeOptions() {
+		prType := ui.ExtractPRType(option)
+		title := generateSimpleTitle("Add login page", prType, "")
+
+		name, err := buildBranchName("", prType, title)
+		if err != nil {
+			t.Errorf("buildBranchName() for %q: %v", option, err)
+			continue
+		}
+		if want := prType + "/add-login-page"; name != want {
+			t.Errorf("buildBranchName() for %q = %q, want %q", option, name, want)
+		}
+	}
+}
+
+func TestBuildCommitMessageForEveryPRType(t *testing.T) {
+	chdirRepoWithConfig(t, "commit:\n  conventional: true\n")
+
+	for _, option := range ui.PRTypeOptions() {
+		prType := ui.ExtractPRType(option)
+		title := generateSimpleTitle("Add login page", prType, "")
+
+		message, err := buildCommitMessage("", prType, title, false)
+		if err != nil {
+			t.Errorf("buildCommitMessage() for %q: %v", option, err)
+			continue
+		}
+		if want := prType + ": Add login page"; message != want {
+			t.Errorf("buildCommitMessage() for %q = %q, want %q", option, message, want)
+		}
+	}
+}
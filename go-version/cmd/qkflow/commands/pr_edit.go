@@ -0,0 +1,285 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/editor"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/jira"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/Wangggym/quick-workflow/internal/watcher"
+	"github.com/spf13/cobra"
+)
+
+var (
+	editTitle        string
+	editBody         string
+	editBodyFile     string
+	editBodyInEditor bool
+	editBodyInWeb    bool
+	editBase         string
+	editAddLabels    []string
+	editRemoveLabels []string
+)
+
+var prEditCmd = &cobra.Command{
+	Use:   "edit [pr]",
+	Short: "Edit a PR's title, body, base branch or labels",
+	Long: `Edit an existing pull request. Without flags, you are asked what to change.
+
+The watching list entry is updated so 'qkflow update' and the watch daemon
+pick up the new title.
+
+Arguments:
+` + prRefHelp + `
+
+Examples:
+  qkflow pr edit 123 --title "PROJ-123: Fix: handle empty input"
+  qkflow pr edit --edit                 # edit body of current branch's PR in $EDITOR
+  qkflow pr edit 123 --web              # edit body in the web editor
+  qkflow pr edit 123 --base release/2.3
+  qkflow pr edit 123 --add-label bug --remove-label wip`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runPREdit,
+}
+
+func init() {
+	prEditCmd.Flags().StringVarP(&editTitle, "title", "t", "", "New PR title")
+	prEditCmd.Flags().StringVarP(&editBody, "body", "b", "", "New PR body")
+	prEditCmd.Flags().StringVar(&editBodyFile, "body-file", "", "Read the new PR body from a file")
+	prEditCmd.Flags().BoolVarP(&editBodyInEditor, "edit", "e", false, "Edit the PR body in $EDITOR")
+	prEditCmd.Flags().BoolVar(&editBodyInWeb, "web", false, "Edit the PR body in the web editor")
+	prEditCmd.Flags().StringVar(&editBase, "base", "", "New base branch")
+	prEditCmd.Flags().StringSliceVar(&editAddLabels, "add-label", []string{}, "Labels to add")
+	prEditCmd.Flags().StringSliceVar(&editRemoveLabels, "remove-label", []string{}, "Labels to remove")
+}
+
+func runPREdit(cmd *cobra.Command, args []string) {
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	resolved := resolvePRArg(ghClient, args, "edit", false)
+	if resolved == nil {
+		return
+	}
+	owner, repo, pr := resolved.Owner, resolved.Repo, resolved.PR
+
+	hasFlags := cmd.Flags().Changed("title") || cmd.Flags().Changed("body") || editBodyFile != "" ||
+		editBodyInEditor || editBodyInWeb || editBase != "" || len(editAddLabels) > 0 || len(editRemoveLabels) > 0
+
+	// 没有参数时交互式选择要修改的内容
+	if !hasFlags {
+		if !promptPREdits(pr) {
+			return
+		}
+	}
+
+	input := github.UpdatePullRequestInput{}
+	if cmd.Flags().Changed("title") || editTitle != "" {
+		if strings.TrimSpace(editTitle) == "" {
+			ui.Error("PR title cannot be empty")
+			return
+		}
+		input.Title = &editTitle
+	}
+
+	body, changed, err := newPRBody(ghClient, owner, repo, pr, cmd.Flags().Changed("body"))
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to get PR body: %v", err))
+		return
+	}
+	if changed {
+		input.Body = &body
+	}
+
+	if editBase != "" && editBase != pr.Base {
+		input.Base = &editBase
+	}
+
+	if input.Title != nil || input.Body != nil || input.Base != nil {
+		ui.Info(fmt.Sprintf("Updating PR #%d...", pr.Number))
+		updated, err := ghClient.UpdatePullRequest(owner, repo, pr.Number, input)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to update PR: %v", err))
+			return
+		}
+		if input.Title != nil {
+			ui.Success(fmt.Sprintf("Title: %s", updated.Title))
+		}
+		if input.Body != nil {
+			ui.Success("Body updated")
+		}
+		if input.Base != nil {
+			ui.Success(fmt.Sprintf("Base branch: %s", updated.Base))
+		}
+		pr = updated
+	}
+
+	if len(editAddLabels) > 0 {
+		if err := ghClient.AddLabels(owner, repo, pr.Number, editAddLabels); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to add labels: %v", err))
+		} else {
+			ui.Success(fmt.Sprintf("Added labels: %s", strings.Join(editAddLabels, ", ")))
+		}
+	}
+	for _, label := range editRemoveLabels {
+		if err := ghClient.RemoveLabel(owner, repo, pr.Number, label); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to remove label: %v", err))
+		} else {
+			ui.Success(fmt.Sprintf("Removed label: %s", label))
+		}
+	}
+
+	syncWatchingPR(owner, repo, pr)
+
+	fmt.Println()
+	ui.Success(fmt.Sprintf("PR updated: %s", pr.HTMLURL))
+}
+
+// promptPREdits asks which parts of the PR to change and fills the edit flags
+func promptPREdits(pr *github.PullRequest) bool {
+	options := []string{
+		"Title",
+		"Body ($EDITOR)",
+		"Body (web editor)",
+		"Base branch",
+		"Labels",
+	}
+
+	selected, err := ui.PromptMultiSelect("What do you want to edit?", options)
+	if err != nil {
+		if err.Error() == "interrupt" {
+			ui.Warning("Operation cancelled by user")
+			os.Exit(0)
+		}
+		ui.Error(fmt.Sprintf("Failed to select: %v", err))
+		return false
+	}
+	if len(selected) == 0 {
+		ui.Info("Nothing to edit")
+		return false
+	}
+
+	for _, choice := range selected {
+		var err error
+		switch choice {
+		case options[0]:
+			ui.Info(fmt.Sprintf("Current title: %s", pr.Title))
+			editTitle, err = ui.PromptInput("New title:", true)
+		case options[1]:
+			editBodyInEditor = true
+		case options[2]:
+			editBodyInWeb = true
+		case options[3]:
+			ui.Info(fmt.Sprintf("Current base branch: %s", pr.Base))
+			editBase, err = ui.PromptInput("New base branch:", true)
+		case options[4]:
+			if len(pr.Labels) > 0 {
+				ui.Info(fmt.Sprintf("Current labels: %s", strings.Join(pr.Labels, ", ")))
+			}
+			var add, remove string
+			add, err = ui.PromptInput("Labels to add (comma separated, optional):", false)
+			if err == nil {
+				remove, err = ui.PromptInput("Labels to remove (comma separated, optional):", false)
+			}
+			editAddLabels = splitList(add)
+			editRemoveLabels = splitList(remove)
+		}
+		if err != nil {
+			if err.Error() == "interrupt" {
+				ui.Warning("Operation cancelled by user")
+				os.Exit(0)
+			}
+			ui.Error(fmt.Sprintf("Failed to get input: %v", err))
+			return false
+		}
+	}
+
+	return true
+}
+
+// newPRBody returns the new PR body from flags, a file, $EDITOR or the web editor
+func newPRBody(ghClient *github.Client, owner, repo string, pr *github.PullRequest, bodyFlagSet bool) (string, bool, error) {
+	switch {
+	case bodyFlagSet:
+		return editBody, true, nil
+
+	case editBodyFile != "":
+		data, err := os.ReadFile(editBodyFile)
+		if err != nil {
+			return "", false, err
+		}
+		return string(data), true, nil
+
+	case editBodyInEditor:
+		content, err := editor.EditText(pr.Body, "qkflow-pr-body-*.md")
+		if err != nil {
+			return "", false, err
+		}
+		content = strings.TrimSpace(content)
+		return content, content != strings.TrimSpace(pr.Body), nil
+
+	case editBodyInWeb:
+		result, err := editor.StartEditor()
+		if err != nil {
+			return "", false, err
+		}
+		if result == nil || (result.Content == "" && len(result.Files) == 0) {
+			ui.Info("Editor cancelled, body unchanged")
+			return "", false, nil
+		}
+
+		content := result.Content
+		if len(result.Files) > 0 {
+			ui.Info(fmt.Sprintf("Uploading %d file(s)...", len(result.Files)))
+			uploads, err := editor.UploadFiles(result.Files, ghClient, nil, pr.Number, owner, repo, "")
+			if err != nil {
+				ui.Warning(fmt.Sprintf("Failed to upload files: %v", err))
+			} else {
+				content = editor.ReplaceLocalPathsWithURLs(content, uploads)
+			}
+		}
+		return content, true, nil
+	}
+
+	return "", false, nil
+}
+
+// syncWatchingPR refreshes the watching list entry of the PR, if any
+func syncWatchingPR(owner, repo string, pr *github.PullRequest) {
+	watchingList, err := watcher.NewWatchingList()
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to load watching list: %v", err))
+		return
+	}
+
+	entry := watchingList.Get(owner, repo, pr.Number)
+	if entry == nil {
+		return
+	}
+
+	updated := *entry
+	updated.Title = pr.Title
+	updated.Branch = pr.Head
+	updated.JiraTickets = jira.ExtractIssueKeys(append(entry.JiraTickets, pr.Title)...)
+	if err := watchingList.Update(updated); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to update watching list: %v", err))
+	}
+}
+
+// splitList splits a comma separated list, dropping empty items
+func splitList(s string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
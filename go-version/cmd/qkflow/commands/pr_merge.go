@@ -2,108 +2,41 @@ package commands
 
 import (
 	"fmt"
-	"os"
 	"os/exec"
-	"strconv"
 	"strings"
 
 	"github.com/Wangggym/quick-workflow/internal/git"
 	"github.com/Wangggym/quick-workflow/internal/github"
 	"github.com/Wangggym/quick-workflow/internal/jira"
+	"github.com/Wangggym/quick-workflow/internal/stack"
 	"github.com/Wangggym/quick-workflow/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var prMergeCmd = &cobra.Command{
-	Use:   "merge [pr-number|pr-url]",
+	Use:   "merge [pr]",
 	Short: "Merge a PR and update Jira status",
 	Long: `Merge a pull request and automatically:
   - Merge the PR on GitHub
   - Delete the remote branch
   - Delete the local branch
   - Update Jira status to Done/Merged
+  - Retarget and restack PRs stacked on it (see 'qkflow stack')
 
 Arguments:
-  [pr-number|pr-url]  PR number (e.g., 123) or full GitHub PR URL
-                      (e.g., https://github.com/owner/repo/pull/123)
-                      Omit to auto-detect from current branch
+` + prRefHelp + `
 
 Examples:
   qkflow pr merge 123
   qkflow pr merge https://github.com/brain/planning-api/pull/2001
+  qkflow pr merge brain/planning-api#2001
+  qkflow pr merge PROJ-123
   qkflow pr merge`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runPRMerge,
 }
 
 func runPRMerge(cmd *cobra.Command, args []string) {
-	var owner, repo string
-	var prNumber int
-	var err error
-
-	// 如果提供了参数，检查是否是 URL 格式
-	if len(args) > 0 {
-		arg := args[0]
-		
-		// 检查是否是 GitHub PR URL
-		if github.IsPRURL(arg) {
-			ui.Info("Detected GitHub PR URL, parsing...")
-			owner, repo, prNumber, err = github.ParsePRFromURL(arg)
-			if err != nil {
-				ui.Error(fmt.Sprintf("Failed to parse PR URL: %v", err))
-				return
-			}
-			ui.Success(fmt.Sprintf("Parsed: %s/%s PR #%d", owner, repo, prNumber))
-		} else {
-			// 尝试作为 PR 号解析
-			prNumber, err = strconv.Atoi(arg)
-			if err != nil {
-				ui.Error(fmt.Sprintf("Invalid PR number or URL: %s", arg))
-				ui.Info("Expected: PR number (e.g., '123') or GitHub URL (e.g., 'https://github.com/owner/repo/pull/123')")
-				return
-			}
-			
-			// PR 号格式，需要从本地仓库获取 owner/repo
-			if !git.IsGitRepository() {
-				ui.Error("Not a git repository. When using PR number, you must be in a git repository.")
-				ui.Info("Alternatively, use the full GitHub PR URL: https://github.com/owner/repo/pull/NUMBER")
-				return
-			}
-			
-			remoteURL, err := git.GetRemoteURL()
-			if err != nil {
-				ui.Error(fmt.Sprintf("Failed to get remote URL: %v", err))
-				return
-			}
-			
-			owner, repo, err = github.ParseRepositoryFromURL(remoteURL)
-			if err != nil {
-				ui.Error(fmt.Sprintf("Failed to parse repository: %v", err))
-				return
-			}
-		}
-	} else {
-		// 没有提供参数，使用原有的自动检测逻辑
-		// 检查是否在 Git 仓库中
-		if !git.IsGitRepository() {
-			ui.Error("Not a git repository")
-			return
-		}
-
-		// 获取仓库信息
-		remoteURL, err := git.GetRemoteURL()
-		if err != nil {
-			ui.Error(fmt.Sprintf("Failed to get remote URL: %v", err))
-			return
-		}
-
-		owner, repo, err = github.ParseRepositoryFromURL(remoteURL)
-		if err != nil {
-			ui.Error(fmt.Sprintf("Failed to parse repository: %v", err))
-			return
-		}
-	}
-
 	// 创建 GitHub 客户端
 	ghClient, err := github.NewClient()
 	if err != nil {
@@ -111,112 +44,16 @@ func runPRMerge(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// 如果还没有 PR 号，尝试自动检测或让用户选择
-	if prNumber == 0 {
-		// 尝试获取当前分支的 PR
-		currentBranch, err := git.GetCurrentBranch()
-		if err == nil && currentBranch != "" {
-			ui.Info(fmt.Sprintf("Checking for PR from current branch: %s", currentBranch))
-			
-		// 先尝试 open 状态的 PR
-		prs, err := ghClient.ListPullRequests(owner, repo, "open", "")
-		if err == nil {
-			for _, pr := range prs {
-				if pr.Head == currentBranch {
-					prNumber = pr.Number
-					ui.Success(fmt.Sprintf("Found PR #%d: %s", pr.Number, pr.Title))
-					break
-				}
-			}
-		}
-		
-		// 如果没找到，尝试所有状态的 PR
-		if prNumber == 0 {
-			allPRs, err := ghClient.ListPullRequests(owner, repo, "all", "")
-				if err == nil {
-					for _, pr := range allPRs {
-						if pr.Head == currentBranch {
-							prNumber = pr.Number
-							ui.Success(fmt.Sprintf("Found PR #%d (%s): %s", pr.Number, pr.State, pr.Title))
-							break
-						}
-					}
-				}
-			}
-			
-			// 如果还是没找到，提示用户
-			if prNumber == 0 {
-				ui.Warning(fmt.Sprintf("No PR found for branch: %s", currentBranch))
-				ui.Info("This branch may not have a PR yet. Please create one first with:")
-				ui.Info("  qkg pr create")
-				fmt.Println()
-				
-				// 询问用户是否手动输入 PR 号
-				manually, err := ui.PromptConfirm("Do you want to manually enter a PR number or select from list?", true)
-				if err != nil || !manually {
-					ui.Info("Merge cancelled")
-					return
-				}
-			}
-		}
-
-	// 如果用户选择手动输入或没有当前分支
-	if prNumber == 0 {
-		prs, err := ghClient.ListPullRequests(owner, repo, "open", "")
-		if err != nil {
-			ui.Error(fmt.Sprintf("Failed to list PRs: %v", err))
-			return
-		}
-
-			if len(prs) == 0 {
-				ui.Error("No open pull requests found")
-				return
-			}
-
-			// 构建选择列表
-			prOptions := make([]string, len(prs))
-			for i, pr := range prs {
-				prOptions[i] = fmt.Sprintf("#%d - %s", pr.Number, pr.Title)
-			}
-
-			selected, err := ui.PromptSelect("Select a PR to merge:", prOptions)
-			if err != nil {
-				if err.Error() == "interrupt" {
-					ui.Warning("Operation cancelled by user")
-					os.Exit(0)
-				}
-				ui.Error(fmt.Sprintf("Failed to select PR: %v", err))
-				return
-			}
-
-			// 从选择中提取 PR 号
-			var selectedPR *github.PullRequest
-			for i, option := range prOptions {
-				if option == selected {
-					selectedPR = &prs[i]
-					break
-				}
-			}
-
-			if selectedPR != nil {
-				prNumber = selectedPR.Number
-			} else {
-				ui.Error("Failed to find selected PR")
-				return
-			}
-		}
-	}
-
-	// 获取 PR 信息
-	ui.Info(fmt.Sprintf("Fetching PR #%d...", prNumber))
-	pr, err := ghClient.GetPullRequest(owner, repo, prNumber)
-	if err != nil {
-		ui.Error(fmt.Sprintf("Failed to get PR: %v", err))
+	// 解析 PR（编号、URL、owner/repo#123、Jira ticket、分支或当前分支）
+	resolved := resolvePRArg(ghClient, args, "merge", true)
+	if resolved == nil {
 		return
 	}
+	owner, repo, pr := resolved.Owner, resolved.Repo, resolved.PR
+	prNumber := pr.Number
 
 	ui.Info(fmt.Sprintf("PR: %s", pr.Title))
-	ui.Info(fmt.Sprintf("Branch: %s -> %s", pr.Head, pr.Base))
+	ui.Info(fmt.Sprintf("Branch: %s -> %s", pr.HeadLabel(owner, repo), pr.Base))
 	ui.Info(fmt.Sprintf("State: %s", pr.State))
 
 	// 检查 PR 状态
@@ -235,10 +72,16 @@ func runPRMerge(cmd *cobra.Command, args []string) {
 		ui.Success("Pull request merged!")
 	}
 
+	// 删除远程分支前先把堆叠在其上的 PR 改为指向它的 base，否则会被 GitHub 自动关闭
+	var stackedChildren []stack.Branch
+	if !alreadyMerged {
+		stackedChildren = retargetStackChildren(ghClient, owner, repo, pr)
+	}
+
 	// 删除远程分支（如果还存在）
 	if !alreadyMerged {
-		ui.Info(fmt.Sprintf("Deleting remote branch %s...", pr.Head))
-		if err := git.DeleteRemoteBranch(pr.Head); err != nil {
+		ui.Info(fmt.Sprintf("Deleting remote branch %s...", pr.HeadLabel(owner, repo)))
+		if err := deleteHeadBranch(ghClient, owner, repo, pr); err != nil {
 			ui.Warning(fmt.Sprintf("Failed to delete remote branch: %v (may already be deleted)", err))
 		} else {
 			ui.Success("Remote branch deleted")
@@ -329,10 +172,38 @@ func runPRMerge(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if len(stackedChildren) > 0 {
+		restackAfterMerge(ghClient, owner, repo, len(stackedChildren))
+	}
+
 	fmt.Println()
 	ui.Success("All done! 🎉")
 }
 
+// restackAfterMerge offers to rebase the branches stacked on a merged PR
+func restackAfterMerge(ghClient *github.Client, owner, repo string, count int) {
+	fmt.Println()
+	ok, err := ui.PromptConfirm(fmt.Sprintf("Restack %d dependent PR(s) now?", count), true)
+	if err != nil || !ok {
+		ui.Info("Run 'qkflow stack sync' later to restack dependent PRs")
+		return
+	}
+
+	// 只能在该仓库的本地克隆中 rebase
+	localOwner, localRepo, err := github.GetCurrentRepository()
+	if err != nil || !strings.EqualFold(localOwner, owner) || !strings.EqualFold(localRepo, repo) {
+		ui.Warning(fmt.Sprintf("Not in a clone of %s/%s, run 'qkflow stack sync' there to restack", owner, repo))
+		return
+	}
+
+	st, err := stack.Load(owner, repo)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to load stack: %v", err))
+		return
+	}
+	syncStack(ghClient, st)
+}
+
 func extractJiraTicket(title string) string {
 	// 尝试从标题中提取 Jira ticket，格式通常是 "PROJ-123: Title"
 	parts := strings.Split(title, ":")
@@ -0,0 +1,345 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/jira"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	revertReason     string
+	revertBase       string
+	revertJiraStatus string
+)
+
+var prRevertCmd = &cobra.Command{
+	Use:   "revert [pr]",
+	Short: "Revert a merged PR and reopen its Jira ticket(s)",
+	Long: `Revert a merged pull request:
+  - Create a revert branch from the default branch (or --base)
+  - Revert the merge (or squash) commit, or every commit of a rebase merge
+  - Open a PR titled "revert: <original title>"
+  - Transition the linked Jira ticket(s) back to the "reopened" status
+  - Comment on the original PR and the Jira ticket(s)
+
+The reopened status is asked for the first time a PR of a Jira project is
+reverted and saved with the project's status mapping. Override it with
+--jira-status.
+
+When the revert stops on conflicts, resolve them, run 'git revert --continue'
+and run the same command again.
+
+Arguments:
+` + prRefHelp + `
+
+Examples:
+  qkflow pr revert 123
+  qkflow pr revert 123 -r "Breaks checkout on Safari"
+  qkflow pr revert 123 --base release/2.3 --jira-status "In Progress"`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runPRRevert,
+}
+
+func init() {
+	prRevertCmd.Flags().StringVarP(&revertReason, "reason", "r", "", "Why the PR is reverted (added to the PR body and comments)")
+	prRevertCmd.Flags().StringVar(&revertBase, "base", "", "Branch to revert on (default: the repository's default branch)")
+	prRevertCmd.Flags().StringVar(&revertJiraStatus, "jira-status", "", "Transition linked Jira ticket(s) to this status")
+}
+
+func runPRRevert(cmd *cobra.Command, args []string) {
+	if !git.IsGitRepository() {
+		ui.Error("Not a git repository")
+		return
+	}
+
+	if git.RevertInProgress() {
+		ui.Error("A revert is in progress")
+		ui.Info("Finish it with 'git revert --continue' (or 'git revert --abort') and run the command again")
+		return
+	}
+
+	hasChanges, err := git.HasUncommittedChanges()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to check git status: %v", err))
+		return
+	}
+	if hasChanges {
+		ui.Error("You have uncommitted changes, commit or stash them first")
+		return
+	}
+
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	resolved := resolvePRArg(ghClient, args, "revert", true)
+	if resolved == nil {
+		return
+	}
+	owner, repo, pr := resolved.Owner, resolved.Repo, resolved.PR
+
+	if !pr.Merged {
+		ui.Error(fmt.Sprintf("PR #%d is not merged (state: %s)", pr.Number, pr.State))
+		return
+	}
+	if pr.MergeCommitSHA == "" {
+		ui.Error(fmt.Sprintf("PR #%d has no merge commit", pr.Number))
+		return
+	}
+
+	rc, err := resolveRepoContext(false)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to get repository info: %v", err))
+		return
+	}
+	if !strings.EqualFold(rc.BaseOwner, owner) || !strings.EqualFold(rc.BaseRepo, repo) {
+		ui.Error(fmt.Sprintf("PR belongs to %s/%s but this clone is %s/%s", owner, repo, rc.BaseOwner, rc.BaseRepo))
+		return
+	}
+
+	base := revertBase
+	if base == "" {
+		base, err = git.GetDefaultBranchOf(rc.BaseRemote)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to detect default branch: %v", err))
+			return
+		}
+	}
+	if base != pr.Base {
+		ui.Warning(fmt.Sprintf("PR #%d was merged into %s, reverting on %s", pr.Number, pr.Base, base))
+	}
+
+	originalBranch, err := git.GetCurrentBranch()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to get current branch: %v", err))
+		return
+	}
+
+	ui.Info(fmt.Sprintf("Fetching %s/%s...", rc.BaseRemote, base))
+	if err := git.FetchBranch(rc.BaseRemote, base); err != nil {
+		ui.Error(fmt.Sprintf("Failed to fetch: %v", err))
+		return
+	}
+	if pr.Base != base {
+		if err := git.FetchBranch(rc.BaseRemote, pr.Base); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to fetch %s: %v", pr.Base, err))
+		}
+	}
+	if _, err := git.RevParse(pr.MergeCommitSHA); err != nil {
+		ui.Error(fmt.Sprintf("Merge commit %s not found locally: %v", shortSHA(pr.MergeCommitSHA), err))
+		return
+	}
+
+	// rebase 合并的 PR 从最新的提交开始逐个还原
+	commits := mergedCommits(ghClient, rc, pr)
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+
+	branch := fmt.Sprintf("revert/%d", pr.Number)
+	remoteBase := rc.BaseRemote + "/" + base
+
+	if existing, err := ghClient.FindPRsByBranch(owner, repo, rc.HeadRef(branch), "open"); err == nil && len(existing) > 0 {
+		ui.Info(fmt.Sprintf("Revert PR already exists: %s", existing[0].HTMLURL))
+		return
+	}
+
+	// 再次运行时，已解决冲突的分支直接推送
+	reverted, err := checkoutResolvedBranch(ghClient, rc, branch, remoteBase)
+	if err != nil {
+		ui.Error(err.Error())
+		return
+	}
+	if reverted {
+		ui.Info(fmt.Sprintf("Reusing %s with the resolved revert", branch))
+	}
+
+	if !reverted {
+		if err := git.CreateBranchFrom(branch, remoteBase); err != nil {
+			ui.Error(fmt.Sprintf("Failed to create branch: %v", err))
+			return
+		}
+
+		ui.Info(fmt.Sprintf("Reverting %s on %s...", describeCommits(commits), branch))
+		if err := git.Revert(commits...); err != nil {
+			if git.RevertInProgress() {
+				fmt.Println()
+				ui.Warning("Revert stopped on conflicts. To continue:")
+				fmt.Println("  1. Resolve the conflicts and 'git add' the files")
+				fmt.Println("  2. git revert --continue")
+				fmt.Printf("  3. qkflow pr revert %d\n", pr.Number)
+				ui.Info(fmt.Sprintf("Or give up with 'git revert --abort && git checkout %s'", originalBranch))
+				return
+			}
+			ui.Error(fmt.Sprintf("Revert failed: %v", err))
+			git.CheckoutBranch(originalBranch)
+			return
+		}
+		ui.Success("Reverted")
+	}
+
+	ui.Info(fmt.Sprintf("Pushing %s to %s...", branch, rc.HeadRemote))
+	if err := git.PushTo(rc.HeadRemote, branch); err != nil {
+		ui.Error(fmt.Sprintf("Failed to push: %v", err))
+		return
+	}
+
+	tickets := linkedJiraTickets(owner, repo, pr)
+
+	revertPR, err := ghClient.CreatePullRequest(github.CreatePullRequestInput{
+		Owner: owner,
+		Repo:  repo,
+		Title: "revert: " + pr.Title,
+		Body:  buildRevertBody(pr, commits, tickets),
+		Head:  rc.HeadRef(branch),
+		Base:  base,
+	})
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create PR: %v", err))
+		ui.Info(fmt.Sprintf("The revert is pushed on %s, rerun the command to retry", branch))
+		return
+	}
+	ui.Success(fmt.Sprintf("Pull request created: %s", revertPR.HTMLURL))
+
+	if err := git.CheckoutBranch(originalBranch); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to switch back to %s: %v", originalBranch, err))
+	}
+
+	comment := fmt.Sprintf("Reverted in #%d", revertPR.Number)
+	if revertReason != "" {
+		comment += ": " + revertReason
+	}
+	if err := ghClient.AddPRComment(owner, repo, pr.Number, comment); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to comment on the original PR: %v", err))
+	}
+
+	// revert PR 不加入 watching list，否则合并后会把 ticket 再次改为已合并状态
+	reopenRevertedTickets(tickets, pr, revertPR)
+
+	copyToClipboard(revertPR.HTMLURL)
+
+	fmt.Println()
+	ui.Success("All done! 🎉")
+}
+
+// buildRevertBody builds the body of a revert PR
+func buildRevertBody(pr *github.PullRequest, commits []string, tickets []string) string {
+	var body strings.Builder
+
+	body.WriteString(fmt.Sprintf("Reverts #%d\n\n", pr.Number))
+	body.WriteString(fmt.Sprintf("Original PR: %s\n", pr.HTMLURL))
+	if len(commits) == 1 {
+		body.WriteString(fmt.Sprintf("Reverted commit: %s\n\n", commits[0]))
+	} else {
+		body.WriteString(fmt.Sprintf("Reverted commits: %s\n\n", strings.Join(commits, ", ")))
+	}
+
+	if revertReason != "" {
+		body.WriteString(fmt.Sprintf("#### Reason:\n\n%s\n\n", revertReason))
+	}
+
+	if links := jiraLinks(tickets); len(links) > 0 {
+		body.WriteString(fmt.Sprintf("#### Jira Link:\n\n%s\n", strings.Join(links, "\n")))
+	}
+
+	return body.String()
+}
+
+// reopenRevertedTickets moves the tickets to the reopened status and comments on them
+func reopenRevertedTickets(tickets []string, pr, revertPR *github.PullRequest) {
+	if len(tickets) == 0 {
+		ui.Info("No Jira ticket linked to this PR")
+		return
+	}
+
+	jiraClient, err := jira.NewClient()
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to create Jira client: %v", err))
+		return
+	}
+
+	comment := fmt.Sprintf("PR #%d (%s) was reverted in %s", pr.Number, pr.HTMLURL, revertPR.HTMLURL)
+	if revertReason != "" {
+		comment += "\n\nReason: " + revertReason
+	}
+
+	for _, ticket := range tickets {
+		status := revertJiraStatus
+		if status == "" {
+			status = revertedStatusFor(jiraClient, jira.ExtractProjectKey(ticket))
+		}
+
+		if status != "" {
+			ui.Info(fmt.Sprintf("Updating %s status to: %s", ticket, status))
+			if err := jiraClient.UpdateStatus(ticket, status); err != nil {
+				ui.Warning(fmt.Sprintf("Failed to update status: %v", err))
+			} else {
+				ui.Success(fmt.Sprintf("Updated %s status to: %s", ticket, status))
+			}
+		}
+
+		if err := jiraClient.AddComment(ticket, comment); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to comment on %s: %v", ticket, err))
+		} else {
+			ui.Success(fmt.Sprintf("Added comment to %s", ticket))
+		}
+
+		if err := jiraClient.AddPRLink(ticket, revertPR.HTMLURL); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to add PR link to %s: %v", ticket, err))
+		}
+	}
+}
+
+// revertedStatusFor returns the configured reopened status of a project,
+// asking for it and saving it in the status mapping the first time
+func revertedStatusFor(jiraClient *jira.Client, projectKey string) string {
+	statusCache, err := jira.NewStatusCache()
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to create status cache: %v", err))
+		return ""
+	}
+
+	mapping, err := statusCache.GetProjectStatus(projectKey)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to get cached status: %v", err))
+		return ""
+	}
+	if mapping != nil && mapping.PRRevertedStatus != "" {
+		return mapping.PRRevertedStatus
+	}
+
+	statuses, err := jiraClient.GetProjectStatuses(projectKey)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to get project statuses: %v", err))
+		return ""
+	}
+
+	ui.Info(fmt.Sprintf("No reopened status configured for project %s yet", projectKey))
+	status, err := ui.PromptSelect("Status for PR reverted:", statuses)
+	if err != nil {
+		if err.Error() == "interrupt" {
+			ui.Warning("Operation cancelled by user")
+			os.Exit(0)
+		}
+		ui.Warning(fmt.Sprintf("Failed to select status: %v", err))
+		return ""
+	}
+
+	// 项目还没有状态映射时不保存，避免 pr create 跳过映射配置
+	if mapping != nil {
+		mapping.PRRevertedStatus = status
+		if err := statusCache.SaveProjectStatus(mapping); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to save status mapping: %v", err))
+		}
+	}
+
+	return status
+}
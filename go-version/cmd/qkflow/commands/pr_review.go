@@ -0,0 +1,497 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/editor"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reviewApprove        bool
+	reviewRequestChanges bool
+	reviewCommentOnly    bool
+	reviewBody           string
+	reviewCommentsFile   string
+	reviewEdit           bool
+	reviewShowResolved   bool
+	reviewThreadIDs      []string
+	reviewReplyTo        int64
+)
+
+var prReviewCmd = &cobra.Command{
+	Use:   "review [pr]",
+	Short: "Review a PR (approve, request changes or comment)",
+	Long: `Submit a review on a pull request:
+  - Approve, request changes or leave a comment-only review
+  - Add inline comments from a file or from $EDITOR
+  - Use "suggestion:" to propose a code change
+
+Inline comment format (one per line, indented lines continue the comment):
+  path/to/file.go:42: message
+  path/to/file.go:40-42: comment on a range of lines
+  path/to/file.go:42: suggestion: replacement code
+
+Arguments:
+` + prRefHelp + `
+
+Examples:
+  qkflow pr review 123 --approve
+  qkflow pr review 123 --request-changes -b "Please add tests"
+  qkflow pr review 123 --comment -f review.txt
+  qkflow pr review 123 --edit
+  qkflow pr review threads 123
+  qkflow pr review resolve 123
+  qkflow pr review reply 123 --to 987654 -b "Fixed"`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runPRReview,
+}
+
+var prReviewThreadsCmd = &cobra.Command{
+	Use:   "threads [pr]",
+	Short: "List review threads of a PR",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runPRReviewThreads,
+}
+
+var prReviewResolveCmd = &cobra.Command{
+	Use:   "resolve [pr]",
+	Short: "Resolve review threads (optionally replying first)",
+	Long: `Resolve review threads of a pull request.
+Without --thread, unresolved threads are listed for selection.
+With -b, the reply is posted to each thread before resolving it.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runPRReviewResolve,
+}
+
+var prReviewReplyCmd = &cobra.Command{
+	Use:   "reply [pr]",
+	Short: "Reply to a review thread",
+	Long: `Reply to an inline review comment.
+Without --to, unresolved threads are listed for selection.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runPRReviewReply,
+}
+
+func init() {
+	prReviewCmd.Flags().BoolVarP(&reviewApprove, "approve", "a", false, "Approve the PR")
+	prReviewCmd.Flags().BoolVarP(&reviewRequestChanges, "request-changes", "r", false, "Request changes")
+	prReviewCmd.Flags().BoolVar(&reviewCommentOnly, "comment", false, "Comment without approving or requesting changes")
+	prReviewCmd.Flags().StringVarP(&reviewBody, "body", "b", "", "Review body")
+	prReviewCmd.Flags().StringVarP(&reviewCommentsFile, "file", "f", "", "File with inline comments (path:line: message), '-' for stdin")
+	prReviewCmd.Flags().BoolVarP(&reviewEdit, "edit", "e", false, "Write the review body and inline comments in $EDITOR")
+
+	prReviewThreadsCmd.Flags().BoolVar(&reviewShowResolved, "all", false, "Include resolved threads")
+
+	prReviewResolveCmd.Flags().StringSliceVar(&reviewThreadIDs, "thread", []string{}, "Thread ID(s) to resolve")
+	prReviewResolveCmd.Flags().StringVarP(&reviewBody, "body", "b", "", "Reply to post before resolving")
+
+	prReviewReplyCmd.Flags().Int64Var(&reviewReplyTo, "to", 0, "Comment ID to reply to")
+	prReviewReplyCmd.Flags().StringVarP(&reviewBody, "body", "b", "", "Reply text")
+
+	prReviewCmd.AddCommand(prReviewThreadsCmd)
+	prReviewCmd.AddCommand(prReviewResolveCmd)
+	prReviewCmd.AddCommand(prReviewReplyCmd)
+}
+
+const reviewEditorTemplate = `%s
+# Write the review body above. Lines starting with '#' are ignored.
+# Inline comments go below the scissors line, one per line:
+#   path/to/file.go:42: message
+#   path/to/file.go:40-42: comment on a range of lines
+#   path/to/file.go:42: suggestion: replacement code
+# Indented lines continue the previous comment.
+%s
+%s`
+
+func runPRReview(cmd *cobra.Command, args []string) {
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	event, ok := selectReviewEvent()
+	if !ok {
+		return
+	}
+
+	// 读取行内评论
+	body := reviewBody
+	var comments []github.ReviewComment
+	if reviewCommentsFile != "" {
+		comments, err = readReviewCommentsFile(reviewCommentsFile)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to read inline comments: %v", err))
+			return
+		}
+		ui.Info(fmt.Sprintf("Loaded %d inline comment(s) from %s", len(comments), reviewCommentsFile))
+	}
+
+	resolved := resolvePRArg(ghClient, args, "review", false)
+	if resolved == nil {
+		return
+	}
+	owner, repo, pr := resolved.Owner, resolved.Repo, resolved.PR
+
+	if pr.State != "open" {
+		ui.Error(fmt.Sprintf("PR is not open (state: %s)", pr.State))
+		return
+	}
+
+	if reviewEdit {
+		body, comments, err = editReview(body, comments)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to edit review: %v", err))
+			return
+		}
+	}
+
+	// GitHub 要求 request changes / comment 必须有内容
+	if event != github.ReviewEventApprove && body == "" && len(comments) == 0 {
+		body, err = ui.PromptInput("Review comment:", true)
+		if err != nil {
+			if err.Error() == "interrupt" {
+				ui.Warning("Operation cancelled by user")
+				os.Exit(0)
+			}
+			ui.Error(fmt.Sprintf("Failed to get review comment: %v", err))
+			return
+		}
+	}
+
+	ui.Info(fmt.Sprintf("Submitting %s review on PR #%d...", reviewEventLabel(event), pr.Number))
+	if err := ghClient.CreateReview(owner, repo, pr.Number, github.CreateReviewInput{
+		Event:    event,
+		Body:     body,
+		Comments: comments,
+	}); err != nil {
+		ui.Error(fmt.Sprintf("Failed to submit review: %v", err))
+		if strings.Contains(err.Error(), "422") {
+			ui.Info("Check that inline comments point at lines changed in the PR, and that you are not the PR author")
+		}
+		return
+	}
+
+	if len(comments) > 0 {
+		ui.Success(fmt.Sprintf("Review submitted with %d inline comment(s): %s", len(comments), pr.HTMLURL))
+	} else {
+		ui.Success(fmt.Sprintf("Review submitted: %s", pr.HTMLURL))
+	}
+}
+
+// selectReviewEvent returns the review event from flags or asks the user
+func selectReviewEvent() (github.ReviewEvent, bool) {
+	selected := 0
+	event := github.ReviewEventComment
+	if reviewApprove {
+		selected++
+		event = github.ReviewEventApprove
+	}
+	if reviewRequestChanges {
+		selected++
+		event = github.ReviewEventRequestChanges
+	}
+	if reviewCommentOnly {
+		selected++
+		event = github.ReviewEventComment
+	}
+
+	if selected > 1 {
+		ui.Error("Use only one of --approve, --request-changes and --comment")
+		return "", false
+	}
+	if selected == 1 {
+		return event, true
+	}
+
+	options := []string{
+		"✅ Approve",
+		"❌ Request changes",
+		"💬 Comment",
+	}
+	choice, err := ui.PromptSelect("Review type:", options)
+	if err != nil {
+		if err.Error() == "interrupt" {
+			ui.Warning("Operation cancelled by user")
+			os.Exit(0)
+		}
+		ui.Error(fmt.Sprintf("Failed to select review type: %v", err))
+		return "", false
+	}
+
+	switch choice {
+	case options[0]:
+		return github.ReviewEventApprove, true
+	case options[1]:
+		return github.ReviewEventRequestChanges, true
+	default:
+		return github.ReviewEventComment, true
+	}
+}
+
+func reviewEventLabel(event github.ReviewEvent) string {
+	switch event {
+	case github.ReviewEventApprove:
+		return "approve"
+	case github.ReviewEventRequestChanges:
+		return "request-changes"
+	default:
+		return "comment"
+	}
+}
+
+// readReviewCommentsFile parses inline comments from a file or stdin
+func readReviewCommentsFile(path string) ([]github.ReviewComment, error) {
+	if path == "-" {
+		return github.ParseReviewComments(os.Stdin)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return github.ParseReviewComments(file)
+}
+
+// editReview lets the user write the review body and inline comments in $EDITOR
+func editReview(body string, comments []github.ReviewComment) (string, []github.ReviewComment, error) {
+	var inline strings.Builder
+	for _, c := range comments {
+		lines := fmt.Sprintf("%d", c.Line)
+		if c.StartLine > 0 {
+			lines = fmt.Sprintf("%d-%d", c.StartLine, c.Line)
+		}
+		commentBody := strings.ReplaceAll(c.Body, "\n", "\n  ")
+		inline.WriteString(fmt.Sprintf("%s:%s: %s\n", c.Path, lines, commentBody))
+	}
+
+	content, err := editor.EditText(fmt.Sprintf(reviewEditorTemplate, body, editor.ScissorsLine, inline.String()), "qkflow-review-*.md")
+	if err != nil {
+		return "", nil, err
+	}
+
+	before, after := editor.SplitAtScissors(content)
+	parsed, err := github.ParseReviewComments(strings.NewReader(after))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return editor.StripComments(before), parsed, nil
+}
+
+func runPRReviewThreads(cmd *cobra.Command, args []string) {
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	resolved := resolvePRArg(ghClient, args, "list threads for", true)
+	if resolved == nil {
+		return
+	}
+
+	threads, err := ghClient.ListReviewThreads(resolved.Owner, resolved.Repo, resolved.PR.Number)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to list review threads: %v", err))
+		return
+	}
+
+	shown := 0
+	for _, thread := range threads {
+		if thread.IsResolved && !reviewShowResolved {
+			continue
+		}
+		shown++
+		printReviewThread(thread)
+	}
+
+	if shown == 0 {
+		ui.Info("No unresolved review threads")
+	}
+}
+
+func printReviewThread(thread github.ReviewThread) {
+	status := "🟡 unresolved"
+	if thread.IsResolved {
+		status = "✅ resolved"
+	}
+	if thread.IsOutdated {
+		status += " (outdated)"
+	}
+
+	fmt.Printf("\n%s:%d  %s\n", ui.Cyan(thread.Path), thread.Line, status)
+	fmt.Printf("  Thread: %s\n", thread.ID)
+	for _, comment := range thread.Comments {
+		fmt.Printf("  [%d] %s: %s\n", comment.ID, ui.Yellow(comment.Author), strings.ReplaceAll(comment.Body, "\n", "\n      "))
+	}
+}
+
+// selectReviewThreads lets the user pick among unresolved threads
+func selectReviewThreads(ghClient *github.Client, resolved *github.ResolvedPR, message string, multi bool) ([]github.ReviewThread, error) {
+	threads, err := ghClient.ListReviewThreads(resolved.Owner, resolved.Repo, resolved.PR.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	unresolved := make([]github.ReviewThread, 0)
+	options := make([]string, 0)
+	for _, thread := range threads {
+		if thread.IsResolved || len(thread.Comments) == 0 {
+			continue
+		}
+		first := thread.Comments[0]
+		summary := strings.SplitN(first.Body, "\n", 2)[0]
+		unresolved = append(unresolved, thread)
+		options = append(options, fmt.Sprintf("%s:%d - %s: %s", thread.Path, thread.Line, first.Author, truncateString(summary, 60)))
+	}
+
+	if len(unresolved) == 0 {
+		return nil, nil
+	}
+
+	var selected []string
+	if multi {
+		selected, err = ui.PromptMultiSelect(message, options)
+	} else {
+		var choice string
+		choice, err = ui.PromptSelect(message, options)
+		selected = []string{choice}
+	}
+	if err != nil {
+		if err.Error() == "interrupt" {
+			ui.Warning("Operation cancelled by user")
+			os.Exit(0)
+		}
+		return nil, err
+	}
+
+	result := make([]github.ReviewThread, 0, len(selected))
+	for _, choice := range selected {
+		for i, option := range options {
+			if option == choice {
+				result = append(result, unresolved[i])
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func runPRReviewResolve(cmd *cobra.Command, args []string) {
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	resolved := resolvePRArg(ghClient, args, "resolve threads on", true)
+	if resolved == nil {
+		return
+	}
+
+	threads := make([]github.ReviewThread, 0)
+	if len(reviewThreadIDs) > 0 {
+		all, err := ghClient.ListReviewThreads(resolved.Owner, resolved.Repo, resolved.PR.Number)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to list review threads: %v", err))
+			return
+		}
+		for _, id := range reviewThreadIDs {
+			found := false
+			for _, thread := range all {
+				if thread.ID == id {
+					threads = append(threads, thread)
+					found = true
+					break
+				}
+			}
+			if !found {
+				ui.Warning(fmt.Sprintf("Thread %s not found on PR #%d", id, resolved.PR.Number))
+			}
+		}
+	} else {
+		threads, err = selectReviewThreads(ghClient, resolved, "Select threads to resolve:", true)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to select threads: %v", err))
+			return
+		}
+		if len(threads) == 0 {
+			ui.Info("No threads to resolve")
+			return
+		}
+	}
+
+	for _, thread := range threads {
+		if reviewBody != "" && len(thread.Comments) > 0 {
+			if err := ghClient.ReplyToReviewComment(resolved.Owner, resolved.Repo, resolved.PR.Number, thread.Comments[0].ID, reviewBody); err != nil {
+				ui.Warning(fmt.Sprintf("Failed to reply on %s:%d: %v", thread.Path, thread.Line, err))
+			}
+		}
+
+		if err := ghClient.ResolveReviewThread(thread.ID); err != nil {
+			ui.Error(fmt.Sprintf("Failed to resolve thread: %v", err))
+			continue
+		}
+		if thread.Path != "" {
+			ui.Success(fmt.Sprintf("Resolved %s:%d", thread.Path, thread.Line))
+		} else {
+			ui.Success(fmt.Sprintf("Resolved thread %s", thread.ID))
+		}
+	}
+}
+
+func runPRReviewReply(cmd *cobra.Command, args []string) {
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	resolved := resolvePRArg(ghClient, args, "reply on", true)
+	if resolved == nil {
+		return
+	}
+
+	commentID := reviewReplyTo
+	if commentID == 0 {
+		threads, err := selectReviewThreads(ghClient, resolved, "Select a thread to reply to:", false)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to select thread: %v", err))
+			return
+		}
+		if len(threads) == 0 {
+			ui.Info("No unresolved review threads")
+			return
+		}
+		commentID = threads[0].Comments[0].ID
+	}
+
+	body := reviewBody
+	if body == "" {
+		body, err = ui.PromptInput("Reply:", true)
+		if err != nil {
+			if err.Error() == "interrupt" {
+				ui.Warning("Operation cancelled by user")
+				os.Exit(0)
+			}
+			ui.Error(fmt.Sprintf("Failed to get reply: %v", err))
+			return
+		}
+	}
+
+	if err := ghClient.ReplyToReviewComment(resolved.Owner, resolved.Repo, resolved.PR.Number, commentID, body); err != nil {
+		ui.Error(fmt.Sprintf("Failed to reply: %v", err))
+		return
+	}
+
+	ui.Success("Reply posted")
+}
@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	recoverList  bool
+	recoverClean bool
+)
+
+var recoverCmd = &cobra.Command{
+	Use:   "recover [snapshot]",
+	Short: "Restore changes from a recovery snapshot",
+	Long: `Restore HEAD, the staged changes and the working tree (including untracked
+files) from a recovery snapshot.
+
+qkflow takes a snapshot before 'pr create' touches your repository and keeps
+it when the operation fails or is rolled back. Snapshots are stored as git
+refs under refs/qkflow/recovery/, so they survive restarts and 'git gc'.
+
+Your current changes are saved in a new snapshot before restoring, so
+recovering never loses work.
+
+Examples:
+  qkflow recover --list
+  qkflow recover                          # pick a snapshot
+  qkflow recover 20240102-150405-1a2b3c4
+  qkflow recover --clean                  # delete all snapshots`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runRecover,
+}
+
+func init() {
+	recoverCmd.Flags().BoolVarP(&recoverList, "list", "l", false, "List recovery snapshots")
+	recoverCmd.Flags().BoolVar(&recoverClean, "clean", false, "Delete all recovery snapshots")
+}
+
+func runRecover(cmd *cobra.Command, args []string) {
+	if !git.IsGitRepository() {
+		ui.Error("Not a git repository")
+		return
+	}
+
+	snapshots, err := git.ListSnapshots()
+	if err != nil {
+		ui.Error(err.Error())
+		return
+	}
+	if len(snapshots) == 0 {
+		ui.Info("No recovery snapshots")
+		return
+	}
+
+	if recoverList {
+		fmt.Println()
+		for _, s := range snapshots {
+			fmt.Printf("  %s  %s  %s (on %s)\n", ui.Cyan(s.Name()), s.CreatedAt.Format("2006-01-02 15:04"), s.Reason, snapshotBranch(&s))
+		}
+		fmt.Println()
+		return
+	}
+
+	if recoverClean {
+		ok, err := ui.PromptConfirm(fmt.Sprintf("Delete %d recovery snapshot(s)?", len(snapshots)), false)
+		if err != nil || !ok {
+			ui.Info("Clean cancelled")
+			return
+		}
+		for _, s := range snapshots {
+			if err := git.DeleteSnapshot(s.Ref); err != nil {
+				ui.Warning(err.Error())
+			}
+		}
+		ui.Success("Recovery snapshots deleted")
+		return
+	}
+
+	snapshot, err := selectSnapshot(snapshots, args)
+	if err != nil {
+		ui.Error(err.Error())
+		return
+	}
+
+	fmt.Println()
+	ui.Info(fmt.Sprintf("Snapshot %s: %s, taken %s on %s", snapshot.Name(), snapshot.Reason, snapshot.CreatedAt.Format("2006-01-02 15:04"), snapshotBranch(snapshot)))
+	ok, err := ui.PromptConfirm("Restore it? Current changes are saved in a new snapshot first", true)
+	if err != nil || !ok {
+		ui.Info("Recover cancelled")
+		return
+	}
+
+	// 先保存当前改动，恢复操作本身也可以撤销
+	if dirty, _ := git.HasUncommittedChanges(); dirty {
+		current, err := git.CreateSnapshot("before recover")
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to save current changes: %v", err))
+			return
+		}
+		ui.Info(fmt.Sprintf("Saved current changes as %s", current.Name()))
+	}
+
+	target := recoverTarget(snapshot)
+	ui.Info(fmt.Sprintf("Checking out %s...", target))
+	if err := git.ForceCheckout(target); err != nil {
+		ui.Error(err.Error())
+		return
+	}
+
+	if err := snapshot.Restore(); err != nil {
+		ui.Error(err.Error())
+		return
+	}
+
+	ui.Success("Changes restored (staged, unstaged and untracked)")
+	ui.Info(fmt.Sprintf("The snapshot is kept, delete it with 'git update-ref -d %s'", snapshot.Ref))
+}
+
+// selectSnapshot returns the snapshot named in args or lets the user pick one
+func selectSnapshot(snapshots []git.Snapshot, args []string) (*git.Snapshot, error) {
+	if len(args) > 0 {
+		for i := range snapshots {
+			if snapshots[i].Name() == args[0] || snapshots[i].Ref == args[0] {
+				return &snapshots[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no recovery snapshot named %s, see 'qkflow recover --list'", args[0])
+	}
+
+	options := make([]string, 0, len(snapshots))
+	for _, s := range snapshots {
+		options = append(options, fmt.Sprintf("%s  %s (on %s)", s.Name(), s.Reason, snapshotBranch(&s)))
+	}
+
+	selected, err := ui.PromptSelect("Select a snapshot to restore:", options)
+	if err != nil {
+		if err.Error() == "interrupt" {
+			ui.Warning("Operation cancelled by user")
+			os.Exit(0)
+		}
+		return nil, err
+	}
+
+	name, _, _ := strings.Cut(selected, "  ")
+	return selectSnapshot(snapshots, []string{name})
+}
+
+// recoverTarget returns what to check out before restoring: the snapshot's
+// branch if it still points at the snapshot's HEAD, else the commit itself
+func recoverTarget(s *git.Snapshot) string {
+	if s.Branch != "" {
+		if sha, err := git.RevParse(s.Branch); err == nil && sha == s.Head {
+			return s.Branch
+		}
+		ui.Warning(fmt.Sprintf("Branch %s has moved since the snapshot, restoring on a detached HEAD", s.Branch))
+	}
+	return s.Head
+}
+
+func snapshotBranch(s *git.Snapshot) string {
+	if s.Branch == "" {
+		return "detached HEAD"
+	}
+	return s.Branch
+}
@@ -0,0 +1,9 @@
+package commands
+
+import "github.com/Wangggym/quick-workflow/internal/git"
+
+// repository is used by pr create and update for status, branch, commit and
+// push. Their other steps call the package-level functions of internal/git,
+// which act on the process working directory, so it must stay the repository
+// of the working directory.
+var repository git.Repository = git.WorkingDir()
@@ -70,8 +70,12 @@ func init() {
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(jiraCmd)
 	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(branchCmd)
 	rootCmd.AddCommand(watchCmd)
 	rootCmd.AddCommand(aiCmd)
+	rootCmd.AddCommand(stackCmd)
+	rootCmd.AddCommand(recoverCmd)
 }
 
 var versionCmd = &cobra.Command{
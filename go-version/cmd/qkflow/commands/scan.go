@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/scan"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/Wangggym/quick-workflow/pkg/config"
+)
+
+// scanBeforePush looks for credentials, environment files and large files in
+// every commit of base..HEAD, following push_scan in the config and 'scan' in
+// .qkflow.yaml. It returns false when the push must stop, also when the
+// commits can't be read in block mode.
+func scanBeforePush(base string, skip bool) bool {
+	repo, err := loadRepoConfig()
+	if err != nil {
+		ui.Error(err.Error())
+		return false
+	}
+	settings := config.PushScanFor(repo)
+
+	block := true
+	switch settings.Mode {
+	case "", "block":
+	case "warn":
+		block = false
+	case "off":
+		return true
+	default:
+		ui.Error(fmt.Sprintf("Invalid scan mode %q, use block, warn or off", settings.Mode))
+		return false
+	}
+	if skip {
+		ui.Warning("Skipping the scan for secrets and large files (--no-scan)")
+		return true
+	}
+
+	scanner, err := scan.New(scan.Options{
+		Secrets:       configSecrets(),
+		MaxFileSize:   int64(settings.MaxFileSizeMB) << 20,
+		AllowPaths:    settings.AllowPaths,
+		AllowPatterns: settings.AllowPatterns,
+	})
+	if err != nil {
+		ui.Error(err.Error())
+		return false
+	}
+
+	ui.Info("Scanning the changes for secrets and large files...")
+	findings := make([]scan.Finding, 0)
+
+	// 扫描每个提交，之后的提交删除的内容也会被推送
+	files, err := git.BranchHistoryFiles(base)
+	if err != nil {
+		return scanFailed(err, block)
+	}
+	paths := make([]string, 0, len(files))
+	for file := range files {
+		paths = append(paths, file)
+	}
+	sort.Strings(paths)
+	for _, file := range paths {
+		if f := scanner.ScanFile(file, files[file]); f != nil {
+			findings = append(findings, *f)
+		}
+	}
+
+	patches, err := git.BranchPatches(base)
+	if err != nil {
+		return scanFailed(err, block)
+	}
+	findings = append(findings, scanner.ScanDiff(patches)...)
+
+	if len(findings) == 0 {
+		return true
+	}
+
+	if block {
+		ui.Error(fmt.Sprintf("Found %d possible secret(s) or unwanted file(s) in the changes to push:", len(findings)))
+	} else {
+		ui.Warning(fmt.Sprintf("Found %d possible secret(s) or unwanted file(s) in the changes to push:", len(findings)))
+	}
+	for _, f := range findings {
+		fmt.Printf("  %s\n", f)
+	}
+	if !block {
+		return true
+	}
+
+	fmt.Println()
+	ui.Info("Remove them from the commits (e.g. git rm --cached <file> and amend), or if they are fine:")
+	fmt.Printf("  - add '%s' to the line\n", scan.AllowMarker)
+	fmt.Printf("  - list the file under scan.allow_paths in %s\n", config.RepoConfigFile)
+	fmt.Println("  - push once with --no-scan")
+	return false
+}
+
+// scanFailed reports a scan that couldn't read the commits. In block mode the
+// push stops, since the commits may have secrets.
+func scanFailed(err error, block bool) bool {
+	if !block {
+		ui.Warning(fmt.Sprintf("Scan skipped: %v", err))
+		return true
+	}
+	ui.Error(fmt.Sprintf("Failed to scan the changes to push: %v", err))
+	ui.Info("Fix the problem, or push once with --no-scan")
+	return false
+}
+
+// configSecrets returns the credentials of the qkflow config, which must never be pushed
+func configSecrets() map[string]string {
+	cfg := config.Get()
+	if cfg == nil {
+		return nil
+	}
+
+	return map[string]string{
+		"Jira API token":   cfg.JiraAPIToken,
+		"GitHub token":     cfg.GitHubToken,
+		"OpenAI key":       cfg.OpenAIKey,
+		"DeepSeek key":     cfg.DeepSeekKey,
+		"OpenAI proxy key": cfg.OpenAIProxyKey,
+		"Cerebras key":     cfg.CerebrasKey,
+	}
+}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/Wangggym/quick-workflow/pkg/config"
+)
+
+// commitSigning returns how to sign commits: the --sign flag, else
+// commit_signing and signing_key from the config
+func commitSigning(format string) (git.Signing, error) {
+	signing := git.Signing{Format: format}
+	if cfg := config.Get(); cfg != nil {
+		if signing.Format == "" {
+			signing.Format = cfg.CommitSigning
+		}
+		signing.Key = cfg.SigningKey
+	}
+
+	return signing, git.ValidateSigningFormat(signing.Format)
+}
+
+// signingRequired reports whether branch protection or a ruleset requires
+// signed commits on branch. Errors are reported and treated as not required.
+func signingRequired(ghClient *github.Client, owner, repo, branch string) bool {
+	required, err := ghClient.RequiresSignedCommits(owner, repo, branch)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Could not check whether %s requires signed commits: %v", branch, err))
+		return false
+	}
+	return required
+}
+
+// checkSigningSetup fails when signed commits are required but the commits
+// qkflow creates would not be signed
+func checkSigningSetup(branch string, signing git.Signing) bool {
+	if git.WillSign(signing) {
+		return true
+	}
+
+	ui.Error(fmt.Sprintf("%s requires signed commits, but commit signing is not configured", branch))
+	printSigningHelp()
+	return false
+}
+
+// checkCommitsSigned fails before pushing when commits in base..HEAD are not signed
+func checkCommitsSigned(base string) bool {
+	unsigned, err := git.UnsignedCommits(base, "HEAD")
+	if err != nil {
+		ui.Warning(err.Error())
+		return true
+	}
+	if len(unsigned) == 0 {
+		return true
+	}
+
+	ui.Error(fmt.Sprintf("Signed commits are required, but %d commit(s) are not signed:", len(unsigned)))
+	for _, commit := range unsigned {
+		fmt.Printf("  %s\n", commit)
+	}
+	ui.Info(fmt.Sprintf("Sign them with: git rebase --exec 'git commit --amend --no-edit -S' %s", base))
+	printSigningHelp()
+	return false
+}
+
+func printSigningHelp() {
+	ui.Info("Sign commits with --sign gpg|ssh, or in the qkflow config:")
+	fmt.Println("  commit_signing: ssh                   # or gpg")
+	fmt.Println("  signing_key: ~/.ssh/id_ed25519.pub    # optional, defaults to git's user.signingkey")
+	ui.Info("or for every commit with: git config --global commit.gpgsign true")
+}
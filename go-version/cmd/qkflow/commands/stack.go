@@ -0,0 +1,442 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/stack"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var stackCmd = &cobra.Command{
+	Use:   "stack",
+	Short: "Manage stacked pull requests",
+	Long: `Work with stacks of pull requests, where each PR is based on the previous one.
+
+Create stacked PRs with 'qkflow pr create --stack' from the branch the new
+PR should build on.
+
+Available commands:
+  status  - Show the stacked branches and their PRs
+  sync    - Rebase descendants and retarget their PRs after a parent merges`,
+}
+
+var stackStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the PR stacks of the current repository",
+	Run:   runStackStatus,
+}
+
+var stackSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Restack branches after a parent PR merged or changed",
+	Long: `Bring the stacks of the current repository up to date:
+  - Branches whose parent PR was merged are moved onto the parent's base
+    and their PR is retargeted
+  - Branches are rebased onto their (updated) parent branch
+  - Rebased branches with an open PR are pushed with --force-with-lease
+  - Merged branches are removed from the stack
+
+If a rebase stops on conflicts, resolve them, run 'git rebase --continue'
+and then 'qkflow stack sync' again.`,
+	Run: runStackSync,
+}
+
+func init() {
+	stackCmd.AddCommand(stackStatusCmd)
+	stackCmd.AddCommand(stackSyncCmd)
+}
+
+// loadRepoStack loads the stack of the repository in the current directory
+func loadRepoStack() (*stack.Stack, error) {
+	owner, repo, err := github.GetCurrentRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository info: %w", err)
+	}
+
+	return stack.Load(owner, repo)
+}
+
+func runStackStatus(cmd *cobra.Command, args []string) {
+	if !git.IsGitRepository() {
+		ui.Error("Not a git repository")
+		return
+	}
+
+	st, err := loadRepoStack()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to load stack: %v", err))
+		return
+	}
+
+	if len(st.Branches) == 0 {
+		ui.Info("No stacked branches. Create one with 'qkflow pr create --stack'")
+		return
+	}
+
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	currentBranch, _ := git.GetCurrentBranch()
+	prs := fetchStackPRs(ghClient, st)
+
+	trunks := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, root := range st.Roots() {
+		if !seen[root.Parent] {
+			seen[root.Parent] = true
+			trunks = append(trunks, root.Parent)
+		}
+	}
+
+	fmt.Println()
+	for _, trunk := range trunks {
+		fmt.Println(ui.Cyan(trunk))
+		printStackChildren(st, prs, trunk, "", currentBranch)
+		fmt.Println()
+	}
+}
+
+// printStackChildren prints the subtree of branches stacked on parent
+func printStackChildren(st *stack.Stack, prs map[string]*github.PullRequest, parent, indent, currentBranch string) {
+	children := st.Children(parent)
+	for i, b := range children {
+		connector, childIndent := "├─ ", "│  "
+		if i == len(children)-1 {
+			connector, childIndent = "└─ ", "   "
+		}
+
+		line := b.Name
+		if b.Name == currentBranch {
+			line = ui.Green(b.Name + " *")
+		}
+
+		if pr := prs[b.Name]; pr != nil {
+			line += fmt.Sprintf("  #%d %s", pr.Number, stackPRState(pr))
+		} else if b.PRNumber > 0 {
+			line += fmt.Sprintf("  #%d", b.PRNumber)
+		} else {
+			line += "  " + ui.Yellow("no PR")
+		}
+
+		if reason := needsRestack(st, prs, b); reason != "" {
+			line += "  " + ui.Yellow("⚠ "+reason)
+		}
+
+		fmt.Printf("%s%s%s\n", indent, connector, line)
+		printStackChildren(st, prs, b.Name, indent+childIndent, currentBranch)
+	}
+}
+
+func stackPRState(pr *github.PullRequest) string {
+	switch {
+	case pr.Merged:
+		return ui.Magenta("merged")
+	case pr.State == "open":
+		return ui.Green("open")
+	default:
+		return ui.Red(pr.State)
+	}
+}
+
+// needsRestack explains why a branch is out of date with its parent, or returns ""
+func needsRestack(st *stack.Stack, prs map[string]*github.PullRequest, b stack.Branch) string {
+	if pr := prs[b.Parent]; pr != nil && pr.Merged {
+		return "parent merged, run 'qkflow stack sync'"
+	}
+	if st.Get(b.Parent) != nil && git.BranchExists(b.Parent) && git.BranchExists(b.Name) && !git.IsAncestor(b.Parent, b.Name) {
+		return "parent changed, run 'qkflow stack sync'"
+	}
+	return ""
+}
+
+// pushRestackedBranch force-pushes a rebased branch with lease as the expected
+// remote commit. It doesn't push when the remote branch has commits that
+// weren't in the branch before the rebase (head), as they would be lost.
+func pushRestackedBranch(remote, branch, lease, head string) {
+	switch {
+	case lease == "":
+		ui.Warning(fmt.Sprintf("Not pushing %s: %s/%s was not fetched before the sync", branch, remote, branch))
+		ui.Info(fmt.Sprintf("Check the remote branch, then push with 'git push --force-with-lease %s %s'", remote, branch))
+		return
+	case !git.IsAncestor(lease, head):
+		ui.Warning(fmt.Sprintf("Not pushing %s: %s/%s has commits that are not in the local branch", branch, remote, branch))
+		ui.Info(fmt.Sprintf("Integrate them, then push with 'git push --force-with-lease %s %s'", remote, branch))
+		return
+	}
+
+	if err := git.PushForceWithLeaseTo(remote, branch, lease); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to push %s: %v", branch, err))
+		return
+	}
+	ui.Success(fmt.Sprintf("Pushed %s", branch))
+}
+
+// fetchStackPRs gets the PR of each stacked branch, keyed by branch name
+func fetchStackPRs(ghClient *github.Client, st *stack.Stack) map[string]*github.PullRequest {
+	prs := make(map[string]*github.PullRequest)
+	for _, b := range st.Branches {
+		if b.PRNumber == 0 {
+			continue
+		}
+		pr, err := ghClient.GetPullRequest(st.Owner, st.Repo, b.PRNumber)
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Failed to get PR #%d: %v", b.PRNumber, err))
+			continue
+		}
+		prs[b.Name] = pr
+	}
+	return prs
+}
+
+func runStackSync(cmd *cobra.Command, args []string) {
+	if !git.IsGitRepository() {
+		ui.Error("Not a git repository")
+		return
+	}
+
+	ghClient, err := github.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		return
+	}
+
+	st, err := loadRepoStack()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to load stack: %v", err))
+		return
+	}
+
+	if syncStack(ghClient, st) {
+		fmt.Println()
+		ui.Success("Stack is up to date! 🎉")
+	}
+}
+
+// syncStack restacks all branches of st and reports whether it completed
+func syncStack(ghClient *github.Client, st *stack.Stack) bool {
+	if len(st.Branches) == 0 {
+		ui.Info("No stacked branches")
+		return true
+	}
+
+	if git.RebaseInProgress() {
+		ui.Error("A rebase is in progress. Finish it with 'git rebase --continue' (or 'git rebase --abort') first")
+		return false
+	}
+
+	hasChanges, err := git.HasUncommittedChanges()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to check git status: %v", err))
+		return false
+	}
+	if hasChanges {
+		ui.Error("You have uncommitted changes. Commit or stash them before restacking")
+		return false
+	}
+
+	// 栈的分支和 PR 都在同一个仓库，和 pr create --stack 一样不支持 fork
+	rc, err := github.DetectRepoContext()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to get repository info: %v", err))
+		return false
+	}
+	if rc.IsFork() {
+		ui.Error("Stacks are not supported for PRs from a fork")
+		return false
+	}
+	remote := rc.BaseRemote
+
+	originalBranch, _ := git.GetCurrentBranch()
+
+	// fetch 之前的远程分支位置作为强推的 lease，期间别人推送的提交不会被覆盖
+	leases := make(map[string]string)
+	for _, b := range st.Branches {
+		leases[b.Name], _ = git.RevParse(remote + "/" + b.Name)
+	}
+
+	ui.Info(fmt.Sprintf("Fetching %s...", remote))
+	if err := git.Fetch(remote); err != nil {
+		ui.Error(fmt.Sprintf("Failed to fetch: %v", err))
+		return false
+	}
+
+	prs := fetchStackPRs(ghClient, st)
+	merged := make([]string, 0)
+
+	for _, ordered := range st.Ordered() {
+		b := st.Get(ordered.Name)
+
+		if pr := prs[b.Name]; pr != nil && pr.Merged {
+			merged = append(merged, b.Name)
+			continue
+		}
+
+		// 跳过已合并的祖先，挂到第一个未合并的祖先（或主干）上
+		reparented := false
+		for {
+			parent := st.Get(b.Parent)
+			if parent == nil {
+				break
+			}
+			if pr := prs[parent.Name]; pr == nil || !pr.Merged {
+				break
+			}
+			b.Parent = parent.Parent
+			reparented = true
+		}
+
+		if pr := prs[b.Name]; pr != nil && pr.State == "open" && pr.Base != b.Parent {
+			ui.Info(fmt.Sprintf("Retargeting PR #%d: %s -> %s", pr.Number, pr.Base, b.Parent))
+			base := b.Parent
+			if _, err := ghClient.UpdatePullRequest(st.Owner, st.Repo, pr.Number, github.UpdatePullRequestInput{Base: &base}); err != nil {
+				ui.Warning(fmt.Sprintf("Failed to retarget PR #%d: %v", pr.Number, err))
+			}
+		}
+
+		if !git.BranchExists(b.Name) {
+			ui.Warning(fmt.Sprintf("Local branch %s not found, skipping rebase", b.Name))
+			continue
+		}
+
+		// 栈内父分支用本地分支，主干用远程分支
+		onto := b.Parent
+		if st.Get(b.Parent) == nil || !git.BranchExists(b.Parent) {
+			onto = remote + "/" + b.Parent
+		}
+		ontoSHA, err := git.RevParse(onto)
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Cannot find %s, skipping %s: %v", onto, b.Name, err))
+			continue
+		}
+
+		if git.IsAncestor(ontoSHA, b.Name) {
+			b.ParentSHA = ontoSHA
+			continue
+		}
+		// 主干前进但父分支未变时不需要 rebase，避免无意义的强推
+		if !reparented && st.Get(b.Parent) == nil {
+			continue
+		}
+
+		upstream := b.ParentSHA
+		if upstream == "" || !git.IsAncestor(upstream, b.Name) {
+			upstream, err = git.MergeBase(onto, b.Name)
+			if err != nil {
+				ui.Warning(fmt.Sprintf("Skipping %s: %v", b.Name, err))
+				continue
+			}
+		}
+
+		head, _ := git.RevParse(b.Name)
+
+		ui.Info(fmt.Sprintf("Rebasing %s onto %s...", b.Name, onto))
+		if err := git.RebaseOnto(ontoSHA, upstream, b.Name); err != nil {
+			if err := st.Save(); err != nil {
+				ui.Warning(fmt.Sprintf("Failed to save stack: %v", err))
+			}
+			ui.Error(fmt.Sprintf("Rebase of %s stopped on conflicts", b.Name))
+			fmt.Println()
+			fmt.Println("To continue:")
+			fmt.Println("  1. Resolve the conflicts and 'git add' the files")
+			fmt.Println("  2. Run 'git rebase --continue'")
+			fmt.Println("  3. Run 'qkflow stack sync' again")
+			fmt.Println("Or run 'git rebase --abort' to give up")
+			return false
+		}
+		b.ParentSHA = ontoSHA
+		ui.Success(fmt.Sprintf("Rebased %s", b.Name))
+
+		if pr := prs[b.Name]; pr != nil && pr.State == "open" {
+			pushRestackedBranch(remote, b.Name, leases[b.Name], head)
+		}
+	}
+
+	for _, name := range merged {
+		st.Remove(name)
+		ui.Info(fmt.Sprintf("Removed merged branch %s from the stack", name))
+	}
+
+	if err := st.Save(); err != nil {
+		ui.Error(fmt.Sprintf("Failed to save stack: %v", err))
+		return false
+	}
+
+	// rebase 会切换分支，回到原来的分支
+	if originalBranch != "" && git.BranchExists(originalBranch) {
+		if current, _ := git.GetCurrentBranch(); current != originalBranch {
+			if err := git.CheckoutBranch(originalBranch); err != nil {
+				ui.Warning(fmt.Sprintf("Failed to switch back to %s: %v", originalBranch, err))
+			}
+		}
+	}
+
+	return true
+}
+
+// retargetStackChildren points the PRs stacked on a merged PR at its base branch,
+// so they are not closed by GitHub when the merged branch is deleted
+func retargetStackChildren(ghClient *github.Client, owner, repo string, merged *github.PullRequest) []stack.Branch {
+	st, err := stack.Load(owner, repo)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to load stack: %v", err))
+		return nil
+	}
+
+	children := st.Children(merged.Head)
+	for _, child := range children {
+		if child.PRNumber == 0 {
+			continue
+		}
+		ui.Info(fmt.Sprintf("Retargeting stacked PR #%d to %s...", child.PRNumber, merged.Base))
+		base := merged.Base
+		if _, err := ghClient.UpdatePullRequest(owner, repo, child.PRNumber, github.UpdatePullRequestInput{Base: &base}); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to retarget PR #%d: %v", child.PRNumber, err))
+		}
+	}
+
+	return children
+}
+
+// registerStackBranch records a new branch stacked on parent
+func registerStackBranch(ghClient *github.Client, owner, repo, branch, parent, parentSHA string, prNumber int) {
+	st, err := stack.Load(owner, repo)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to load stack: %v", err))
+		return
+	}
+
+	// 父分支还不在栈中时，以它的 PR 目标分支作为栈底
+	if st.Get(parent) == nil {
+		parentPR, err := ghClient.GetPRByBranch(owner, repo, parent)
+		if err == nil && parentPR != nil {
+			base := parentPR.Base
+			parentParentSHA, _ := git.MergeBase("origin/"+base, parent)
+			st.Set(stack.Branch{Name: parent, Parent: base, PRNumber: parentPR.Number, ParentSHA: parentParentSHA})
+		}
+	}
+
+	st.Set(stack.Branch{Name: branch, Parent: parent, PRNumber: prNumber, ParentSHA: parentSHA})
+
+	if err := st.Save(); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to save stack: %v", err))
+		return
+	}
+
+	chain := []string{branch}
+	for name := parent; ; {
+		chain = append([]string{name}, chain...)
+		b := st.Get(name)
+		if b == nil {
+			break
+		}
+		name = b.Parent
+	}
+	ui.Info(fmt.Sprintf("Stack: %s", strings.Join(chain, " → ")))
+}
@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/scan"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+)
+
+// stagingPlan describes which changes go into the next commit
+type stagingPlan struct {
+	StagedOnly bool     // 只提交已经暂存的内容
+	Pathspecs  []string // 要暂存的路径，为空时暂存全部
+}
+
+// planStaging decides what to commit:
+//   - --paths: the matching changes, added to what is already staged
+//   - content already staged with 'git add': exactly that
+//   - otherwise all changes, picked interactively when picker is set
+//
+// Untracked files that look like secrets, build outputs or large binaries are
+// left out unless the user confirms them. It returns false when there is
+// nothing to commit or the user gave up.
+func planStaging(paths []string, interactive, picker bool) (*stagingPlan, bool) {
+	files, err := repository.Status()
+	if err != nil {
+		ui.Error(err.Error())
+		return nil, false
+	}
+	if len(files) == 0 {
+		ui.Error("No changes to commit")
+		return nil, false
+	}
+
+	if len(paths) > 0 {
+		return planPathsStaging(files, paths)
+	}
+
+	// 用户已经有选择地暂存过，只提交暂存区
+	var staged, leftOut []git.FileStatus
+	for _, f := range files {
+		if f.IsStaged() {
+			staged = append(staged, f)
+		}
+		if f.IsUnstaged() {
+			leftOut = append(leftOut, f)
+		}
+	}
+	if len(staged) > 0 {
+		ui.Info(fmt.Sprintf("Committing the %d staged file(s):", len(staged)))
+		for _, f := range staged {
+			fmt.Printf("  %s\n", describeFile(f))
+		}
+		if len(leftOut) > 0 {
+			ui.Info(fmt.Sprintf("%d file(s) with unstaged changes are left out:", len(leftOut)))
+			for _, f := range leftOut {
+				fmt.Printf("  %s\n", describeFile(f))
+			}
+		}
+		return &stagingPlan{StagedOnly: true}, true
+	}
+
+	// 只有一个普通文件时无需选择
+	if picker && interactive && !(len(files) == 1 && suspiciousReason(files[0]) == "") {
+		return pickFilesToStage(files)
+	}
+
+	var suspicious, safe []git.FileStatus
+	for _, f := range files {
+		if reason := suspiciousReason(f); reason != "" {
+			suspicious = append(suspicious, f)
+			ui.Warning(fmt.Sprintf("%s looks like it shouldn't be committed: %s", f.Path, reason))
+		} else {
+			safe = append(safe, f)
+		}
+	}
+	if len(suspicious) == 0 {
+		return &stagingPlan{}, true
+	}
+
+	include := false
+	if interactive {
+		include, err = ui.PromptConfirm("Include these files in the commit?", false)
+		if err != nil {
+			if err.Error() == "interrupt" {
+				ui.Warning("Operation cancelled by user")
+				os.Exit(0)
+			}
+			include = false
+		}
+	}
+	if include {
+		return &stagingPlan{}, true
+	}
+
+	ui.Info(fmt.Sprintf("Leaving out %d file(s), add them later with 'git add' if needed", len(suspicious)))
+	if len(safe) == 0 {
+		ui.Error("No changes left to commit")
+		return nil, false
+	}
+	return &stagingPlan{Pathspecs: filePathspecs(safe)}, true
+}
+
+// planPathsStaging stages the changes matching --paths
+func planPathsStaging(files []git.FileStatus, paths []string) (*stagingPlan, bool) {
+	matched, err := git.PathsToStage(paths)
+	if err != nil {
+		ui.Error(err.Error())
+		return nil, false
+	}
+	if len(matched) == 0 {
+		ui.Error(fmt.Sprintf("No changes match %v", paths))
+		return nil, false
+	}
+
+	byPath := make(map[string]git.FileStatus, len(files))
+	for _, f := range files {
+		byPath[f.Path] = f
+	}
+
+	ui.Info(fmt.Sprintf("Staging %d file(s):", len(matched)))
+	for _, path := range matched {
+		f, ok := byPath[path]
+		if !ok {
+			fmt.Printf("  %s\n", path)
+			continue
+		}
+		fmt.Printf("  %s\n", describeFile(f))
+		if reason := suspiciousReason(f); reason != "" {
+			ui.Warning(fmt.Sprintf("%s looks like it shouldn't be committed: %s", f.Path, reason))
+		}
+	}
+
+	return &stagingPlan{Pathspecs: paths}, true
+}
+
+// pickFilesToStage lets the user choose the files to commit. Suspicious
+// untracked files are not selected by default.
+func pickFilesToStage(files []git.FileStatus) (*stagingPlan, bool) {
+	options := make([]string, 0, len(files))
+	defaults := make([]string, 0, len(files))
+	byOption := make(map[string]git.FileStatus, len(files))
+
+	for _, f := range files {
+		option := describeFile(f)
+		reason := suspiciousReason(f)
+		if reason != "" {
+			option = fmt.Sprintf("%s  ⚠️  %s", option, reason)
+		} else {
+			defaults = append(defaults, option)
+		}
+		options = append(options, option)
+		byOption[option] = f
+	}
+
+	selected, err := ui.PromptMultiSelectDefaults("Select files to commit:", options, defaults)
+	if err != nil {
+		if err.Error() == "interrupt" {
+			ui.Warning("Operation cancelled by user")
+			os.Exit(0)
+		}
+		ui.Error(fmt.Sprintf("Failed to select files: %v", err))
+		return nil, false
+	}
+	if len(selected) == 0 {
+		ui.Error("No files selected")
+		return nil, false
+	}
+
+	picked := make([]git.FileStatus, 0, len(selected))
+	for _, option := range selected {
+		picked = append(picked, byOption[option])
+	}
+	if len(picked) == len(files) {
+		return &stagingPlan{}, true
+	}
+	return &stagingPlan{Pathspecs: filePathspecs(picked)}, true
+}
+
+// applyStaging stages the changes chosen by planStaging
+func applyStaging(plan stagingPlan) error {
+	switch {
+	case plan.StagedOnly:
+		// 暂存区中的内容保持原样
+		if !git.HasStagedChanges() {
+			return fmt.Errorf("nothing is staged anymore")
+		}
+		return nil
+	case len(plan.Pathspecs) > 0:
+		return git.StagePaths(plan.Pathspecs)
+	default:
+		return repository.AddAll()
+	}
+}
+
+// suspiciousReason only reports untracked files, tracked files were committed on purpose before
+func suspiciousReason(f git.FileStatus) string {
+	if !f.IsUntracked() {
+		return ""
+	}
+	return scan.SuspiciousReason(f.Path, f.Size)
+}
+
+// filePathspecs returns pathspecs matching exactly the given files
+func filePathspecs(files []git.FileStatus) []string {
+	pathspecs := make([]string, 0, len(files))
+	for _, f := range files {
+		pathspecs = append(pathspecs, git.FilePathspec(f.Path))
+		if f.OrigPath != "" {
+			pathspecs = append(pathspecs, git.FilePathspec(f.OrigPath))
+		}
+	}
+	return pathspecs
+}
+
+// describeFile formats a file with its status and diff stats, e.g. "main.go  +12 -3"
+func describeFile(f git.FileStatus) string {
+	switch {
+	case f.IsUntracked():
+		return fmt.Sprintf("%s  (new, %s)", f.Path, formatSize(f.Size))
+	case f.OrigPath != "":
+		return fmt.Sprintf("%s → %s  +%d -%d", f.OrigPath, f.Path, f.Added, f.Deleted)
+	case f.Added < 0:
+		return fmt.Sprintf("%s  (binary)", f.Path)
+	case f.Index == 'D' || f.Worktree == 'D':
+		return fmt.Sprintf("%s  (deleted) -%d", f.Path, f.Deleted)
+	default:
+		return fmt.Sprintf("%s  +%d -%d", f.Path, f.Added, f.Deleted)
+	}
+}
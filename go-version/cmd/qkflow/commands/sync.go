@@ -0,0 +1,223 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/github"
+	"github.com/Wangggym/quick-workflow/internal/ui"
+	"github.com/Wangggym/quick-workflow/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncMerge  bool
+	syncRebase bool
+	syncNoPush bool
+	syncSign   string
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Bring the current branch up to date with its base branch",
+	Long: `Fetch and rebase the current branch onto its base: the base branch of its
+open PR, or the default branch of the repository.
+
+sync_strategy: merge in the config (or --merge) merges the base branch instead
+of rebasing. Local changes are stashed during the sync and restored afterwards.
+
+On conflicts the rebase or merge is left in progress with instructions to
+finish or abort it. When the branch has an open PR, the result is pushed;
+rebased history is pushed with --force-with-lease. When the remote branch has
+commits the local branch doesn't, sync stops before rebasing so they are never
+overwritten; integrate them first, e.g. with 'git pull --rebase'.`,
+	Run: runSync,
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncMerge, "merge", false, "Merge the base branch instead of rebasing")
+	syncCmd.Flags().BoolVar(&syncRebase, "rebase", false, "Rebase onto the base branch (overrides sync_strategy: merge)")
+	syncCmd.Flags().BoolVar(&syncNoPush, "no-push", false, "Don't push the branch after syncing")
+	syncCmd.Flags().StringVar(&syncSign, "sign", "", "Sign rebased or merge commits with gpg or ssh (default: commit_signing from config, else git config)")
+}
+
+func runSync(cmd *cobra.Command, args []string) {
+	if !git.IsGitRepository() {
+		ui.Error("Not a git repository")
+		return
+	}
+
+	if git.RebaseInProgress() {
+		ui.Error("A rebase is in progress. Finish it with 'git rebase --continue' (or 'git rebase --abort') first")
+		return
+	}
+	if git.MergeInProgress() {
+		ui.Error("A merge is in progress. Finish it with 'git commit' (or 'git merge --abort') first")
+		return
+	}
+
+	merge, err := syncUsesMerge()
+	if err != nil {
+		ui.Error(err.Error())
+		return
+	}
+
+	signing, err := commitSigning(syncSign)
+	if err != nil {
+		ui.Error(err.Error())
+		return
+	}
+
+	branch, err := git.GetCurrentBranch()
+	if err != nil || branch == "" || branch == "HEAD" {
+		ui.Error("Not on a branch, check out the branch to sync first")
+		return
+	}
+
+	// 找到分支的 PR 以确定 base 分支（fork 模式下 PR 在 upstream，分支在 fork）
+	pushRemote, baseRemote, baseBranch := "origin", "origin", ""
+	var pr *github.PullRequest
+	signRequired := false
+	if rc, err := github.DetectRepoContext(); err == nil {
+		pushRemote, baseRemote = rc.HeadRemote, rc.BaseRemote
+		if ghClient, err := github.NewClient(); err == nil {
+			if pr, err = ghClient.GetPRByBranch(rc.BaseOwner, rc.BaseRepo, rc.HeadRef(branch)); err == nil && pr != nil {
+				baseBranch = pr.Base
+				ui.Info(fmt.Sprintf("PR #%d targets %s", pr.Number, pr.Base))
+				signRequired = signingRequired(ghClient, rc.BaseOwner, rc.BaseRepo, pr.Base) ||
+					signingRequired(ghClient, rc.HeadOwner, rc.HeadRepo, branch)
+			} else {
+				pr = nil
+			}
+		} else {
+			ui.Warning(fmt.Sprintf("Failed to create GitHub client: %v", err))
+		}
+	}
+	if baseBranch == "" {
+		baseBranch, err = git.GetDefaultBranchOf(baseRemote)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to detect the default branch: %v", err))
+			return
+		}
+	}
+	if branch == baseBranch {
+		ui.Error(fmt.Sprintf("You are on %s itself, check out a feature branch to sync", baseBranch))
+		return
+	}
+	base := baseRemote + "/" + baseBranch
+
+	// rebase 或 merge 会创建新提交，要求签名时先检查签名配置
+	if signRequired && !checkSigningSetup(branch, signing) {
+		return
+	}
+
+	ui.Info(fmt.Sprintf("Fetching %s...", baseRemote))
+	if err := git.Fetch(baseRemote); err != nil {
+		ui.Error(fmt.Sprintf("Failed to fetch: %v", err))
+		return
+	}
+	if pushRemote != baseRemote {
+		if err := git.Fetch(pushRemote); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to fetch %s: %v", pushRemote, err))
+		}
+	}
+
+	if git.IsAncestor(base, "HEAD") {
+		ui.Success(fmt.Sprintf("%s is already up to date with %s", branch, base))
+		return
+	}
+
+	// 改写前远程分支和 HEAD 的位置，用于 --force-with-lease
+	remoteSHA, _ := git.RevParse(pushRemote + "/" + branch)
+	oldHead, _ := git.RevParse("HEAD")
+
+	// 远程分支有本地没有的提交时，rebase 后的强推会覆盖它们
+	if remoteSHA != "" && !git.IsAncestor(remoteSHA, "HEAD") {
+		ui.Error(fmt.Sprintf("%s/%s has commits that %s doesn't have", pushRemote, branch, branch))
+		ui.Info(fmt.Sprintf("Integrate them first, e.g. with 'git pull --rebase %s %s', then run sync again", pushRemote, branch))
+		return
+	}
+
+	if merge {
+		ui.Info(fmt.Sprintf("Merging %s into %s...", base, branch))
+		if err := git.MergeAutostash(base, signing); err != nil {
+			ui.Error(err.Error())
+			if git.MergeInProgress() {
+				printSyncConflictHelp(true, "")
+			}
+			return
+		}
+	} else {
+		ui.Info(fmt.Sprintf("Rebasing %s onto %s...", branch, base))
+		if err := git.RebaseAutostash(base, signing); err != nil {
+			ui.Error(err.Error())
+			if git.RebaseInProgress() {
+				printSyncConflictHelp(false, "")
+			}
+			return
+		}
+	}
+	ui.Success(fmt.Sprintf("%s is up to date with %s", branch, base))
+
+	if pr == nil || syncNoPush {
+		if pr == nil {
+			ui.Info("No open PR for this branch, not pushing")
+		}
+		return
+	}
+
+	if signRequired && !checkCommitsSigned(base) {
+		return
+	}
+	if !pushBranch(pushRemote, branch, remoteSHA, oldHead) {
+		return
+	}
+
+	ui.Success(fmt.Sprintf("✅ PR #%d updated", pr.Number))
+}
+
+// syncUsesMerge decides between merge and rebase: the flags, else sync_strategy
+func syncUsesMerge() (bool, error) {
+	if syncMerge && syncRebase {
+		return false, fmt.Errorf("--merge and --rebase can't be combined")
+	}
+	if syncMerge || syncRebase {
+		return syncMerge, nil
+	}
+
+	strategy := ""
+	if cfg := config.Get(); cfg != nil {
+		strategy = cfg.SyncStrategy
+	}
+	switch strategy {
+	case "", "rebase":
+		return false, nil
+	case "merge":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid sync_strategy %q, use rebase or merge", strategy)
+}
+
+// printSyncConflictHelp explains how to finish a rebase or merge stopped on
+// conflicts; next replaces the last step, pushing the result, when not empty
+func printSyncConflictHelp(merge bool, next string) {
+	fmt.Println()
+	fmt.Println("To continue:")
+	fmt.Println("  1. Resolve the conflicts and 'git add' the files")
+	if merge {
+		fmt.Println("  2. Run 'git commit' to finish the merge")
+		if next == "" {
+			next = "Push with 'git push', or 'qkflow update' if you have more changes"
+		}
+		fmt.Printf("  3. %s\n", next)
+		fmt.Println("Or run 'git merge --abort' to give up")
+	} else {
+		fmt.Println("  2. Run 'git rebase --continue'")
+		if next == "" {
+			next = "Push with 'git push --force-with-lease'"
+		}
+		fmt.Printf("  3. %s\n", next)
+		fmt.Println("Or run 'git rebase --abort' to give up")
+	}
+	fmt.Println("Stashed local changes are restored once it is finished or aborted")
+}
@@ -3,26 +3,77 @@ package commands
 import (
 	"fmt"
 
+	"github.com/Wangggym/quick-workflow/internal/ai"
 	"github.com/Wangggym/quick-workflow/internal/git"
 	"github.com/Wangggym/quick-workflow/internal/github"
 	"github.com/Wangggym/quick-workflow/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+var (
+	updatePaths       []string
+	updateInteractive bool
+	updateNoVerify    bool
+	updateNoScan      bool
+	updateNoChecks    bool
+	updateSign        string
+	updateMessage     string
+	updateAI          bool
+	updateAmend       bool
+	updateFixup       string
+	updateAutosquash  bool
+)
+
 var updateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Quick update - commit and push with PR title as commit message",
 	Long: `Automatically get the current PR title and use it as the commit message.
 This command will:
 1. Get the PR title for the current branch
-2. Stage all changes (git add --all)
+2. Stage the changes (all of them by default)
 3. Commit with the PR title as the message
 4. Push to origin
 
-If no PR is found, it will use "update" as the default commit message.`,
+If no PR is found, it will use "update" as the default commit message.
+Use -m to give the message, or --ai to generate one from the staged diff.
+
+Content already staged with 'git add' is committed as is. Use --paths to stage
+only some changes, or -i to pick the files. Untracked files that look like
+secrets, build outputs or large binaries are only committed after confirmation.
+
+--amend adds the changes to the last commit (keeping its message unless -m or
+--ai is given) and --fixup <commit> creates a "fixup!" commit for an earlier
+one. --autosquash folds the fixup commits of the branch into the commits they
+fix. History rewritten this way is pushed with --force-with-lease against the
+commits it replaced, so commits pushed by someone else in the meantime are
+never overwritten. Any other push is a normal push.
+
+The pre-commit and commit-msg hooks run on commit; skip them with --no-verify.
+New changes are scanned for secrets and large files before pushing, like in
+'pr create'; skip the scan with --no-scan. The checks of .qkflow.yaml run on
+the committed changes before pushing, once you trusted them; skip them with
+--no-checks.
+When there is nothing to commit but the branch has commits that aren't pushed
+yet, e.g. because a check failed, update pushes them.
+When the PR's branches require signed commits, update refuses to create an
+unsigned commit; sign with --sign gpg|ssh or commit_signing in the config.`,
 	Run: runUpdate,
 }
 
+func init() {
+	updateCmd.Flags().StringSliceVar(&updatePaths, "paths", []string{}, "Only commit changes under these paths (added to what is already staged)")
+	updateCmd.Flags().BoolVarP(&updateInteractive, "interactive", "i", false, "Pick the files to commit")
+	updateCmd.Flags().BoolVar(&updateNoVerify, "no-verify", false, "Skip the pre-commit and commit-msg hooks")
+	updateCmd.Flags().BoolVar(&updateNoScan, "no-scan", false, "Skip the scan for secrets and large files before pushing")
+	updateCmd.Flags().BoolVar(&updateNoChecks, "no-checks", false, "Skip the checks of .qkflow.yaml before pushing")
+	updateCmd.Flags().StringVar(&updateSign, "sign", "", "Sign the commit with gpg or ssh (default: commit_signing from config, else git config)")
+	updateCmd.Flags().StringVarP(&updateMessage, "message", "m", "", "Commit message (default: the PR title)")
+	updateCmd.Flags().BoolVar(&updateAI, "ai", false, "Generate the commit message from the staged diff with AI")
+	updateCmd.Flags().BoolVar(&updateAmend, "amend", false, "Amend the last commit and force-push with lease")
+	updateCmd.Flags().StringVar(&updateFixup, "fixup", "", "Create a fixup commit for the given commit")
+	updateCmd.Flags().BoolVar(&updateAutosquash, "autosquash", false, "Squash the fixup commits of the branch and force-push with lease")
+}
+
 func runUpdate(cmd *cobra.Command, args []string) {
 	// 检查是否是 git 仓库
 	if !git.IsGitRepository() {
@@ -30,20 +81,29 @@ func runUpdate(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// 检查是否有未提交的更改
-	hasChanges, err := git.HasUncommittedChanges()
+	if err := validateUpdateFlags(); err != nil {
+		ui.Error(err.Error())
+		return
+	}
+
+	signing, err := commitSigning(updateSign)
 	if err != nil {
-		ui.Error(fmt.Sprintf("Failed to check git status: %v", err))
+		ui.Error(err.Error())
 		return
 	}
 
-	if !hasChanges {
-		ui.Warning("No changes to commit")
+	// 检查是否有未提交的更改
+	hasChanges, err := repository.HasUncommittedChanges()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to check git status: %v", err))
 		return
 	}
 
+	// 没有改动时只能修改上一个提交的信息，或者只做 autosquash
+	commitNeeded := hasChanges || (updateAmend && (updateMessage != "" || updateAI))
+
 	// 获取当前分支
-	branch, err := git.GetCurrentBranch()
+	branch, err := repository.CurrentBranch()
 	if err != nil {
 		ui.Error(fmt.Sprintf("Failed to get current branch: %v", err))
 		return
@@ -53,50 +113,270 @@ func runUpdate(cmd *cobra.Command, args []string) {
 
 	// 获取 PR 标题作为 commit message
 	commitMessage := "update" // 默认 commit message
-	
-	// 尝试从 GitHub 获取 PR 标题
-	remoteURL, err := git.GetRemoteURL()
+	prTitle := ""
+
+	// 尝试从 GitHub 获取 PR 标题（fork 模式下 PR 在 upstream，分支在 fork）
+	pushRemote, baseRemote, baseBranch := "origin", "origin", ""
+	signRequired := false
+	rc, err := github.DetectRepoContext()
 	if err == nil {
-		owner, repo, err := github.ParseRepositoryFromURL(remoteURL)
+		pushRemote, baseRemote = rc.HeadRemote, rc.BaseRemote
+		// 创建 GitHub 客户端
+		ghClient, err := github.NewClient()
 		if err == nil {
-			// 创建 GitHub 客户端
-			ghClient, err := github.NewClient()
-			if err == nil {
-				// 尝试获取当前分支的 PR
-				pr, err := ghClient.GetPRByBranch(owner, repo, branch)
-				if err == nil && pr != nil {
-					commitMessage = pr.Title
-					ui.Success(fmt.Sprintf("Got PR title: %s", commitMessage))
-				} else {
-					ui.Warning(fmt.Sprintf("No open PR found for branch %s, using default message 'update'", branch))
-				}
+			// 尝试获取当前分支的 PR
+			pr, err := ghClient.GetPRByBranch(rc.BaseOwner, rc.BaseRepo, rc.HeadRef(branch))
+			if err == nil && pr != nil {
+				prTitle, baseBranch = pr.Title, pr.Base
+				commitMessage = pr.Title
+				ui.Success(fmt.Sprintf("Got PR title: %s", commitMessage))
+				signRequired = signingRequired(ghClient, rc.BaseOwner, rc.BaseRepo, pr.Base) ||
+					signingRequired(ghClient, rc.HeadOwner, rc.HeadRepo, branch)
 			} else {
-				ui.Warning(fmt.Sprintf("Failed to create GitHub client: %v, using default message", err))
+				ui.Warning(fmt.Sprintf("No open PR found for branch %s, using default message 'update'", branch))
 			}
+		} else {
+			ui.Warning(fmt.Sprintf("Failed to create GitHub client: %v, using default message", err))
 		}
 	}
+	if baseBranch == "" {
+		baseBranch, _ = repository.DefaultBranch(baseRemote)
+	}
+	base := baseRemote + "/" + baseBranch
 
-	// Stage all changes
-	ui.Info("Staging all changes...")
-	if err := git.AddAll(); err != nil {
-		ui.Error(fmt.Sprintf("Failed to stage changes: %v", err))
+	// 分支保护要求签名提交时，不创建未签名的提交
+	if signRequired && !checkSigningSetup(branch, signing) {
 		return
 	}
 
-	// Commit
-	ui.Info(fmt.Sprintf("Committing with message: '%s'", commitMessage))
-	if err := git.Commit(commitMessage); err != nil {
-		ui.Error(fmt.Sprintf("Failed to commit: %v", err))
+	// 已经在 base 分支上的提交不能改写
+	if updateAmend && git.IsAncestor("HEAD", base) {
+		ui.Error(fmt.Sprintf("The last commit is already in %s, amending it would rewrite shared history", base))
 		return
 	}
+	if updateFixup != "" {
+		fixupSHA, err := git.RevParse(updateFixup + "^{commit}")
+		if err != nil || !git.IsAncestor(fixupSHA, "HEAD") {
+			ui.Error(fmt.Sprintf("%s is not a commit of the current branch", updateFixup))
+			return
+		}
+		if git.IsAncestor(fixupSHA, base) {
+			ui.Error(fmt.Sprintf("%s is already in %s and can't be squashed", updateFixup, base))
+			return
+		}
+		updateFixup = fixupSHA
+	}
 
-	// Push
-	ui.Info("Pushing to origin...")
-	if err := git.Push(branch); err != nil {
-		ui.Error(fmt.Sprintf("Failed to push: %v", err))
+	// 改写前远程分支的位置，用于 --force-with-lease
+	remoteSHA, _ := git.RevParse(pushRemote + "/" + branch)
+	// --amend 和 --autosquash 改写前的 HEAD，没有改写时为空
+	rewrittenFrom := ""
+	if updateAmend || updateAutosquash {
+		rewrittenFrom, _ = git.RevParse("HEAD")
+	}
+
+	// 没有改动时，推送之前因为检查失败等原因留在本地的提交
+	if !commitNeeded && !updateAutosquash {
+		if !hasUnpushedCommits(remoteSHA, base) {
+			ui.Warning("No changes to commit")
+			return
+		}
+		ui.Info("No changes to commit, pushing the commits that aren't pushed yet")
+	}
+
+	if commitNeeded {
+		if hasChanges {
+			// 选择要提交的文件
+			staging, ok := planStaging(updatePaths, true, updateInteractive)
+			if !ok {
+				return
+			}
+
+			ui.Info("Staging changes...")
+			if err := applyStaging(*staging); err != nil {
+				ui.Error(fmt.Sprintf("Failed to stage changes: %v", err))
+				return
+			}
+		}
+
+		message, ok := updateCommitMessage(commitMessage, prTitle)
+		if !ok {
+			return
+		}
+
+		// Commit
+		opts := git.CommitOptions{NoVerify: updateNoVerify, Signing: signing, Amend: updateAmend, Fixup: updateFixup}
+		switch {
+		case updateFixup != "":
+			ui.Info(fmt.Sprintf("Creating fixup commit for %s...", shortSHA(updateFixup)))
+		case updateAmend && message == "":
+			ui.Info("Amending the last commit...")
+		default:
+			ui.Info(fmt.Sprintf("Committing with message: '%s'", message))
+		}
+		if err := repository.Commit(message, opts); err != nil {
+			reportCommitError(err)
+			return
+		}
+	}
+
+	if updateAutosquash {
+		ui.Info(fmt.Sprintf("Squashing fixup commits since %s...", base))
+		if !autosquashBranch(base, signing) {
+			return
+		}
+	}
+
+	if signRequired && !checkCommitsSigned(pushRemote+"/"+branch) {
+		printUnpushedHint()
+		return
+	}
+
+	// 只检查远程分支上还没有的改动
+	pushBase := remoteSHA
+	if pushBase == "" {
+		pushBase = base
+	}
+	if !scanBeforePush(pushBase, updateNoScan) {
+		printUnpushedHint()
+		return
+	}
+	if !runRepoChecks(pushBase, updateNoChecks) {
+		printUnpushedHint()
+		return
+	}
+
+	if !pushBranch(pushRemote, branch, remoteSHA, rewrittenFrom) {
+		printUnpushedHint()
 		return
 	}
 
+	if !commitNeeded && !updateAutosquash {
+		ui.Success("✅ Successfully pushed changes!")
+		return
+	}
 	ui.Success("✅ Successfully committed and pushed changes!")
 }
 
+// hasUnpushedCommits reports whether HEAD has commits that the remote branch
+// (remoteSHA, empty when it doesn't exist yet) doesn't have. A branch that
+// isn't pushed yet only counts when it has commits that aren't in base.
+func hasUnpushedCommits(remoteSHA, base string) bool {
+	if remoteSHA == "" {
+		return !git.IsAncestor("HEAD", base)
+	}
+	return !git.IsAncestor("HEAD", remoteSHA)
+}
+
+// printUnpushedHint tells how to push the commits left locally when update stops before pushing
+func printUnpushedHint() {
+	ui.Info("The commits are kept locally, fix the problem and run 'qkflow update' again to push them (or push with 'git push')")
+}
+
+// validateUpdateFlags rejects flag combinations that contradict each other
+func validateUpdateFlags() error {
+	switch {
+	case updateAmend && updateFixup != "":
+		return fmt.Errorf("--amend and --fixup can't be combined")
+	case updateFixup != "" && (updateMessage != "" || updateAI):
+		return fmt.Errorf("--fixup takes the message of the fixed commit, -m and --ai don't apply")
+	case updateMessage != "" && updateAI:
+		return fmt.Errorf("-m and --ai can't be combined")
+	}
+	return nil
+}
+
+// updateCommitMessage picks the message of the commit: -m, generated with
+// --ai, the original one when amending, else the PR title
+func updateCommitMessage(defaultMessage, prTitle string) (string, bool) {
+	switch {
+	case updateFixup != "":
+		return "", true
+	case updateMessage != "":
+		return updateMessage, true
+	case updateAI:
+		return generateCommitMessage(prTitle)
+	case updateAmend:
+		// 保留原提交信息
+		return "", true
+	}
+	return defaultMessage, true
+}
+
+// generateCommitMessage asks the AI client for a message describing the staged diff
+func generateCommitMessage(prTitle string) (string, bool) {
+	// amend 时描述整个修改后的提交
+	diffBase := ""
+	if updateAmend {
+		diffBase = "HEAD~1"
+	}
+	diff, err := git.StagedDiff(diffBase, 12000)
+	if err != nil {
+		ui.Error(err.Error())
+		return "", false
+	}
+
+	aiClient, err := ai.NewClient()
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to create AI client: %v", err))
+		return "", false
+	}
+
+	ui.Info("Generating commit message with AI...")
+	message, err := aiClient.GenerateCommitMessage(diff, prTitle)
+	if err != nil {
+		ui.Error(fmt.Sprintf("AI generation failed: %v", err))
+		ui.Info("Pass the message with -m instead")
+		return "", false
+	}
+	ui.Success(fmt.Sprintf("Generated message: %s", message))
+	return message, true
+}
+
+// autosquashBranch folds the fixup commits after the merge base with base into
+// the commits they fix
+func autosquashBranch(base string, signing git.Signing) bool {
+	mergeBase, err := git.MergeBase("HEAD", base)
+	if err != nil {
+		ui.Error(err.Error())
+		return false
+	}
+
+	if err := git.RebaseAutosquash(mergeBase, signing); err != nil {
+		ui.Error(err.Error())
+		if git.RebaseInProgress() {
+			ui.Info("Resolve the conflicts, then run 'git add <files>' and 'git rebase --continue'")
+			ui.Info("Push the result with 'qkflow update --autosquash' or abort with 'git rebase --abort'")
+		}
+		return false
+	}
+
+	ui.Success("Fixup commits squashed")
+	return true
+}
+
+// pushBranch pushes branch to remote. rewrittenFrom is HEAD before qkflow
+// rewrote the history of the branch, empty when it didn't. Only rewritten
+// history whose old HEAD contained remoteSHA (the remote branch before the
+// rewrite) is pushed with --force-with-lease against remoteSHA, so only the
+// replaced commits are overwritten. Anything else is a normal push, which the
+// remote rejects when it has commits the branch doesn't.
+func pushBranch(remote, branch, remoteSHA, rewrittenFrom string) bool {
+	if remoteSHA != "" && rewrittenFrom != "" && !git.IsAncestor(remoteSHA, "HEAD") && git.IsAncestor(remoteSHA, rewrittenFrom) {
+		ui.Info(fmt.Sprintf("Pushing rewritten history to %s (force with lease)...", remote))
+		if err := git.PushForceWithLeaseTo(remote, branch, remoteSHA); err != nil {
+			ui.Error(fmt.Sprintf("Failed to push: %v", err))
+			ui.Info("The remote branch has commits you don't have yet, fetch and check them before pushing again")
+			return false
+		}
+		return true
+	}
+
+	ui.Info(fmt.Sprintf("Pushing to %s...", remote))
+	if err := repository.PushTo(remote, branch); err != nil {
+		ui.Error(fmt.Sprintf("Failed to push: %v", err))
+		ui.Info("If the remote branch has commits you don't have, fetch and integrate them before pushing again")
+		return false
+	}
+	return true
+}
@@ -195,6 +195,44 @@ Generate the PR title now:`, prType, context)
 	return title, nil
 }
 
+// GenerateCommitMessage generates a commit subject describing a staged diff.
+// prTitle, when set, gives the context of the whole PR.
+func (c *Client) GenerateCommitMessage(diff, prTitle string) (string, error) {
+	prompt := fmt.Sprintf(`You are a professional software engineer writing a git commit message.
+Based on the following staged diff, write a one-line commit subject.
+
+PR Title: %s
+
+Requirements:
+1. Describe WHAT this specific change does, not the whole PR
+2. Be concise and clear (max 72 characters)
+3. Use the imperative mood (e.g. "add", "fix", "remove")
+4. Use English
+5. Only return the commit subject, nothing else
+
+Diff:
+%s
+
+Generate the commit subject now:`, prTitle, diff)
+
+	message, err := c.callChatAPI(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	// 只保留第一行，去掉可能的引号
+	message, _, _ = strings.Cut(message, "\n")
+	message = strings.Trim(strings.TrimSpace(message), "\"'`")
+	if message == "" {
+		return "", fmt.Errorf("AI returned an empty commit message")
+	}
+	if len(message) > 72 {
+		message = message[:69] + "..."
+	}
+
+	return message, nil
+}
+
 // TranslateAndOptimize translates and optimizes a Jira issue title for PR
 func (c *Client) TranslateAndOptimize(title string) (*TranslationResult, error) {
 	// 检测是否需要翻译（包含中文字符）
@@ -0,0 +1,122 @@
+// Package checks runs the check commands a repository declares in its
+// .qkflow.yaml, such as linters and unit tests, on the changed files.
+package checks
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Wangggym/quick-workflow/pkg/config"
+)
+
+// FilesPlaceholder in a check command is replaced with the matching changed files
+const FilesPlaceholder = "{files}"
+
+// Result is the outcome of a check
+type Result struct {
+	Output   string
+	Duration time.Duration
+	Err      error
+}
+
+// MatchingFiles returns the changed files a check applies to. A check without
+// paths applies to all of them.
+func MatchingFiles(check config.Check, files []string) []string {
+	if len(check.Paths) == 0 {
+		return files
+	}
+
+	matched := make([]string, 0)
+	for _, file := range files {
+		for _, pattern := range check.Paths {
+			if MatchPath(pattern, file) {
+				matched = append(matched, file)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// MatchPath matches a path relative to the repository root against a glob.
+// "**" matches any number of directories, and a pattern without "/" matches
+// the file name in any directory, like in .gitignore.
+func MatchPath(pattern, file string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(file))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(file, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// ** 匹配零个或多个目录
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// Command returns the shell command of a check with {files} replaced by the
+// quoted files
+func Command(check config.Check, files []string) string {
+	if !strings.Contains(check.Run, FilesPlaceholder) {
+		return check.Run
+	}
+
+	quoted := make([]string, len(files))
+	for i, file := range files {
+		quoted[i] = shellQuote(file)
+	}
+	return strings.ReplaceAll(check.Run, FilesPlaceholder, strings.Join(quoted, " "))
+}
+
+// Run runs a check command with the shell in dir
+func Run(dir, command string) Result {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Dir = dir
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	start := time.Now()
+	err := cmd.Run()
+	result := Result{Output: output.String(), Duration: time.Since(start)}
+	if err != nil {
+		result.Err = fmt.Errorf("%s: %w", command, err)
+	}
+	return result
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
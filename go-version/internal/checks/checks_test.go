@@ -0,0 +1,62 @@
+package checks
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/pkg/config"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/git/status.go", true},
+		{"*.go", "README.md", false},
+		{"web/**", "web/src/app.ts", true},
+		{"web/**/*.ts", "web/app.ts", true},
+		{"web/**/*.ts", "web/src/deep/app.ts", true},
+		{"web/**/*.ts", "api/app.ts", false},
+		{"**/testdata/*", "internal/scan/testdata/x", true},
+		{"cmd/*.go", "cmd/sub/main.go", false},
+	}
+	for _, tt := range tests {
+		if got := MatchPath(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("MatchPath(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestCommand(t *testing.T) {
+	check := config.Check{Run: "gofmt -l {files}", Paths: []string{"*.go"}}
+	files := MatchingFiles(check, []string{"main.go", "README.md", "dir/it's here.go"})
+
+	want := `gofmt -l main.go 'dir/it'\''s here.go'`
+	if got := Command(check, files); got != want {
+		t.Errorf("Command() = %q, want %q", got, want)
+	}
+
+	all := config.Check{Run: "go test ./..."}
+	if got := MatchingFiles(all, []string{"a", "b"}); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("MatchingFiles() without paths = %v, want all files", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	dir := t.TempDir()
+	if r := Run(dir, "pwd"); r.Err != nil || !strings.Contains(r.Output, filepath.Base(dir)) {
+		t.Errorf("Run(pwd) = %q, %v, want it to run in %s", r.Output, r.Err, dir)
+	}
+	if r := Run(dir, "echo broken; exit 3"); r.Err == nil || !strings.Contains(r.Output, "broken") {
+		t.Errorf("Run() of a failing command = %q, %v, want its output and an error", r.Output, r.Err)
+	}
+}
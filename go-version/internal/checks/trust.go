@@ -0,0 +1,87 @@
+package checks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Wangggym/quick-workflow/internal/utils"
+	"github.com/Wangggym/quick-workflow/pkg/config"
+)
+
+// TrustFile keeps the checks the user allowed to run, in the config directory
+const TrustFile = "trusted-checks.json"
+
+// TrustStore records, per repository, the checks the user allowed to run.
+// The checks are shell commands from a file of the repository, so they only
+// run once the user approved them, and again whenever they change.
+type TrustStore struct {
+	filePath string
+}
+
+// NewTrustStore opens the trust store of the config directory
+func NewTrustStore() (*TrustStore, error) {
+	configDir, err := utils.GetConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return OpenTrustStore(filepath.Join(configDir, TrustFile)), nil
+}
+
+// OpenTrustStore opens the trust store kept in filePath
+func OpenTrustStore(filePath string) *TrustStore {
+	return &TrustStore{filePath: filePath}
+}
+
+// Trusted reports whether the user allowed exactly these checks in the repository at dir
+func (s *TrustStore) Trusted(dir string, checks []config.Check) bool {
+	trusted, err := s.read()
+	if err != nil {
+		return false
+	}
+	return trusted[dir] == Hash(checks)
+}
+
+// Trust records that the user allowed these checks in the repository at dir
+func (s *TrustStore) Trust(dir string, checks []config.Check) error {
+	trusted, err := s.read()
+	if err != nil {
+		return err
+	}
+	trusted[dir] = Hash(checks)
+
+	data, err := json.MarshalIndent(trusted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to save trusted checks: %w", err)
+	}
+	if err := os.WriteFile(s.filePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to save trusted checks: %w", err)
+	}
+	return nil
+}
+
+// read returns the hash of the trusted checks of every repository
+func (s *TrustStore) read() (map[string]string, error) {
+	trusted := make(map[string]string)
+	data, err := os.ReadFile(s.filePath)
+	if os.IsNotExist(err) {
+		return trusted, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trusted checks: %w", err)
+	}
+	if err := json.Unmarshal(data, &trusted); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.filePath, err)
+	}
+	return trusted, nil
+}
+
+// Hash identifies a list of checks; it changes whenever a check does
+func Hash(checks []config.Check) string {
+	data, _ := json.Marshal(checks)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
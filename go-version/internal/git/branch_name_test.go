@@ -0,0 +1,71 @@
+package git
+
+import "testing"
+
+func TestRenderBranchName(t *testing.T) {
+	data := BranchNameData{Ticket: "PROJ-123", Type: "feat", Slug: "add-login", User: "jdoe", Date: "20240102"}
+
+	tests := []struct {
+		tmpl string
+		data BranchNameData
+		want string
+	}{
+		{"", data, "PROJ-123--add-login"},
+		{"", BranchNameData{Slug: "add-login"}, "add-login"},
+		{"feature/{{.Ticket}}-{{.Slug}}", data, "feature/PROJ-123-add-login"},
+		{"{{.Type}}/{{.Ticket}}-{{.Slug}}", BranchNameData{Type: "fix", Slug: "typo"}, "fix/typo"},
+		{"{{.User}}/{{.Date}}-{{lower .Ticket}}", data, "jdoe/20240102-proj-123"},
+	}
+
+	for _, tt := range tests {
+		got, err := RenderBranchName(tt.tmpl, tt.data)
+		if err != nil {
+			t.Errorf("RenderBranchName(%q): %v", tt.tmpl, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("RenderBranchName(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+
+	if _, err := RenderBranchName("{{.Unknown}}", data); err == nil {
+		t.Error("RenderBranchName() with an unknown field should fail")
+	}
+}
+
+func TestBranchSlug(t *testing.T) {
+	tests := []struct {
+		title     string
+		prType    string
+		maxLength int
+		lowercase bool
+		want      string
+	}{
+		{"Feat: Add login page", "feat", 0, false, "Add-login-page"},
+		{"Feat: Add login page", "feat", 0, true, "add-login-page"},
+		{"Fix the  broken -- build", "", 0, true, "fix-the-broken-build"},
+		{"Add support for custom key bindings", "", 20, true, "add-support-for"},
+		{"Add support", "", 11, true, "add-support"},
+	}
+
+	for _, tt := range tests {
+		got := BranchSlug(tt.title, tt.prType, tt.maxLength, tt.lowercase)
+		if got != tt.want {
+			t.Errorf("BranchSlug(%q, %q, %d, %v) = %q, want %q", tt.title, tt.prType, tt.maxLength, tt.lowercase, got, tt.want)
+		}
+	}
+}
+
+func TestValidateBranchName(t *testing.T) {
+	pattern := `^feature/[A-Z]+-\d+-[a-z0-9-]+$`
+
+	if err := ValidateBranchName("feature/PROJ-1-add-login", pattern); err != nil {
+		t.Errorf("valid name rejected: %v", err)
+	}
+	if err := ValidateBranchName("PROJ-1--add-login", pattern); err == nil {
+		t.Error("name not matching the pattern should be rejected")
+	}
+	if err := ValidateBranchName("bad..name", ""); err == nil {
+		t.Error("invalid git branch name should be rejected")
+	}
+}
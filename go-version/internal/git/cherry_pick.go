@@ -0,0 +1,152 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CreateBranchFrom checks out branchName starting at startPoint, resetting the
+// branch if it already exists
+func CreateBranchFrom(branchName, startPoint string) error {
+	cmd := exec.Command("git", "checkout", "-B", branchName, startPoint)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to create branch %s from %s: %w\n%s", branchName, startPoint, err, stderr.String())
+	}
+
+	return nil
+}
+
+// CommitParents returns the parent SHAs of a commit
+func CommitParents(sha string) ([]string, error) {
+	cmd := exec.Command("git", "rev-list", "--parents", "-n", "1", sha)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", sha, err)
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("commit %s not found", sha)
+	}
+	return fields[1:], nil
+}
+
+// CherryPick applies commits on the current branch in order, recording their
+// origin (-x). A single merge commit is picked relative to its first parent.
+// On conflict the cherry-pick is left in progress.
+func CherryPick(shas ...string) error {
+	return applyCommits("cherry-pick", "-x", shas)
+}
+
+// Revert creates commits reverting shas on the current branch, in the given
+// order. A single merge commit is reverted relative to its first parent. On
+// conflict the revert is left in progress.
+func Revert(shas ...string) error {
+	return applyCommits("revert", "--no-edit", shas)
+}
+
+func applyCommits(command, flag string, shas []string) error {
+	args := []string{command, flag}
+	if len(shas) == 1 {
+		parents, err := CommitParents(shas[0])
+		if err != nil {
+			return err
+		}
+		if len(parents) > 1 {
+			args = append(args, "-m", "1")
+		}
+	}
+	args = append(args, shas...)
+
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to %s %s: %w\n%s", command, strings.Join(shas, " "), err, stderr.String())
+	}
+
+	return nil
+}
+
+// CommitRange lists the commits in base..head, oldest first
+func CommitRange(base, head string) ([]string, error) {
+	cmd := exec.Command("git", "rev-list", "--reverse", base+".."+head)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits between %s and %s: %w\n%s", base, head, err, stderr.String())
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// SamePatch reports whether two commits make the same changes, as compared by git patch-id
+func SamePatch(a, b string) bool {
+	idA, errA := patchID(a)
+	idB, errB := patchID(b)
+	return errA == nil && errB == nil && idA != "" && idA == idB
+}
+
+func patchID(sha string) (string, error) {
+	diff, err := exec.Command("git", "diff-tree", "-p", "--no-color", sha).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit %s: %w", sha, err)
+	}
+
+	cmd := exec.Command("git", "patch-id", "--stable")
+	cmd.Stdin = bytes.NewReader(diff)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to compute patch id of %s: %w", sha, err)
+	}
+
+	// 输出格式: <patch-id> <commit>
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], nil
+}
+
+// CherryPickInProgress reports whether a cherry-pick is in progress
+func CherryPickInProgress() bool {
+	return gitPathExists("CHERRY_PICK_HEAD")
+}
+
+// RevertInProgress reports whether a revert is in progress
+func RevertInProgress() bool {
+	return gitPathExists("REVERT_HEAD")
+}
+
+// gitPathExists reports whether a file exists in the git directory
+func gitPathExists(name string) bool {
+	path, err := GitPath(name)
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// SkipCherryPick skips the commit the cherry-pick stopped on and goes on with
+// the remaining ones
+func SkipCherryPick() error {
+	cmd := exec.Command("git", "cherry-pick", "--skip")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to skip cherry-pick: %w\n%s", err, stderr.String())
+	}
+
+	return nil
+}
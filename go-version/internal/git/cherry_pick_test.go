@@ -0,0 +1,44 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/git/testrepo"
+)
+
+func TestCherryPickRange(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+	testrepo.Run(t, ".", "checkout", "-q", "-b", "feature")
+	for _, name := range []string{"b.txt", "c.txt"} {
+		testrepo.WriteFile(t, ".", name, name+"\n")
+		testrepo.Run(t, ".", "add", name)
+		testrepo.Run(t, ".", "commit", "-q", "-m", "add "+name)
+	}
+
+	commits, err := CommitRange("main", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("CommitRange() = %v, want 2 commits", commits)
+	}
+	first := strings.TrimSpace(testrepo.Run(t, ".", "rev-parse", "feature~1"))
+	if commits[0] != first {
+		t.Errorf("CommitRange()[0] = %s, want the oldest commit %s", commits[0], first)
+	}
+
+	testrepo.Run(t, ".", "checkout", "-q", "main")
+	if err := CherryPick(commits...); err != nil {
+		t.Fatal(err)
+	}
+	if !SamePatch("HEAD", "feature") {
+		t.Error("SamePatch() = false for a cherry-picked commit")
+	}
+	if SamePatch("HEAD", "feature~1") {
+		t.Error("SamePatch() = true for different changes")
+	}
+	if subjects := testrepo.Run(t, ".", "log", "--format=%s", "-2"); subjects != "add c.txt\nadd b.txt\n" {
+		t.Errorf("picked commits = %q", subjects)
+	}
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// DefaultCommitTemplate gives "TICKET: title", or just the title without a ticket
+const DefaultCommitTemplate = "{{if .Ticket}}{{.Ticket}}: {{end}}{{.Title}}"
+
+// ConventionalCommitTemplate gives a Conventional Commits subject, "type(scope): summary"
+const ConventionalCommitTemplate = "{{.Type}}{{if .Scope}}({{.Scope}}){{end}}: {{.Summary}}"
+
+// DefaultConventionalTypes are the types allowed when the config lists none
+var DefaultConventionalTypes = []string{
+	"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert",
+}
+
+// CommitMessageData holds the fields available to commit message templates
+type CommitMessageData struct {
+	Ticket  string // Jira ticket，例如 PROJ-123
+	Type    string // 变更类型，例如 feat
+	Scope   string
+	Title   string // PR 标题，例如 "Feat: add login"
+	Summary string // 去掉类型前缀的标题，例如 "add login"
+}
+
+// RenderCommitSubject executes a commit message template into a one-line subject
+func RenderCommitSubject(tmpl string, data CommitMessageData) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultCommitTemplate
+	}
+
+	t, err := template.New("commit").Funcs(templateFuncs).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid commit message template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid commit message template: %w", err)
+	}
+
+	subject := strings.TrimSpace(buf.String())
+	if subject == "" || strings.Contains(subject, "\n") {
+		return "", fmt.Errorf("commit message template must produce a single non-empty line, got %q", subject)
+	}
+	return subject, nil
+}
+
+// BuildCommitMessage joins the subject, an optional body and trailers such
+// as "Co-authored-by: Name <email>" into a commit message
+func BuildCommitMessage(subject, body string, trailers []string) string {
+	var msg strings.Builder
+	msg.WriteString(subject)
+	if body = strings.TrimSpace(body); body != "" {
+		msg.WriteString("\n\n")
+		msg.WriteString(body)
+	}
+	if len(trailers) > 0 {
+		msg.WriteString("\n\n")
+		msg.WriteString(strings.Join(trailers, "\n"))
+	}
+	return msg.String()
+}
+
+// ConventionalCommit is a parsed Conventional Commits subject
+type ConventionalCommit struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+var conventionalRe = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()\s]+)\))?(!)?: (\S.*)$`)
+
+// ParseConventional parses a subject like "feat(auth)!: drop legacy login"
+func ParseConventional(subject string) (*ConventionalCommit, error) {
+	m := conventionalRe.FindStringSubmatch(subject)
+	if m == nil {
+		return nil, fmt.Errorf("%q is not a Conventional Commits subject (type(scope): description)", subject)
+	}
+	return &ConventionalCommit{Type: m[1], Scope: m[2], Breaking: m[3] == "!", Description: m[4]}, nil
+}
+
+// ValidateConventional checks a subject against Conventional Commits with the
+// allowed types (DefaultConventionalTypes when empty) and scopes (any when empty)
+func ValidateConventional(subject string, types, scopes []string) error {
+	cc, err := ParseConventional(subject)
+	if err != nil {
+		return err
+	}
+
+	if len(types) == 0 {
+		types = DefaultConventionalTypes
+	}
+	if !containsString(types, cc.Type) {
+		return fmt.Errorf("commit type %q is not allowed (allowed: %s)", cc.Type, strings.Join(types, ", "))
+	}
+
+	if cc.Scope != "" && len(scopes) > 0 && !containsString(scopes, cc.Scope) {
+		return fmt.Errorf("commit scope %q is not allowed (allowed: %s)", cc.Scope, strings.Join(scopes, ", "))
+	}
+
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package git
+
+import "testing"
+
+func TestRenderCommitSubject(t *testing.T) {
+	data := CommitMessageData{Ticket: "PROJ-1", Type: "feat", Scope: "auth", Title: "Feat: add login", Summary: "add login"}
+
+	tests := []struct {
+		tmpl string
+		data CommitMessageData
+		want string
+	}{
+		{"", data, "PROJ-1: Feat: add login"},
+		{"", CommitMessageData{Title: "add login"}, "add login"},
+		{ConventionalCommitTemplate, data, "feat(auth): add login"},
+		{ConventionalCommitTemplate, CommitMessageData{Type: "fix", Summary: "typo"}, "fix: typo"},
+		{"{{.Type}}: {{.Summary}} [{{.Ticket}}]", data, "feat: add login [PROJ-1]"},
+	}
+
+	for _, tt := range tests {
+		got, err := RenderCommitSubject(tt.tmpl, tt.data)
+		if err != nil {
+			t.Errorf("RenderCommitSubject(%q): %v", tt.tmpl, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("RenderCommitSubject(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+
+	if _, err := RenderCommitSubject("{{.Summary}}\n\nbody", data); err == nil {
+		t.Error("multi-line subject should be rejected")
+	}
+}
+
+func TestBuildCommitMessage(t *testing.T) {
+	got := BuildCommitMessage("feat: add login", "Jira: https://jira/browse/PROJ-1", []string{"Co-authored-by: A <a@example.com>"})
+	want := "feat: add login\n\nJira: https://jira/browse/PROJ-1\n\nCo-authored-by: A <a@example.com>"
+	if got != want {
+		t.Errorf("BuildCommitMessage() = %q, want %q", got, want)
+	}
+
+	if got := BuildCommitMessage("fix: typo", "", nil); got != "fix: typo" {
+		t.Errorf("BuildCommitMessage() without body = %q", got)
+	}
+}
+
+func TestValidateConventional(t *testing.T) {
+	tests := []struct {
+		subject string
+		types   []string
+		scopes  []string
+		valid   bool
+	}{
+		{"feat: add login", nil, nil, true},
+		{"feat(auth)!: drop legacy login", nil, []string{"auth"}, true},
+		{"feat(ui): add login", nil, []string{"auth"}, false},
+		{"feature: add login", nil, nil, false},
+		{"ops: rotate keys", []string{"ops"}, nil, true},
+		{"PROJ-1: Feat: add login", nil, nil, false},
+		{"feat:add login", nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateConventional(tt.subject, tt.types, tt.scopes)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateConventional(%q) error = %v, want valid=%v", tt.subject, err, tt.valid)
+		}
+	}
+}
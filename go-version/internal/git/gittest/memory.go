@@ -0,0 +1,195 @@
+// Package gittest provides git repositories for tests: an in-memory
+// git.Repository that needs no git binary, and temporary on-disk repositories.
+package gittest
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+)
+
+// Commit is a commit recorded by MemoryRepository
+type Commit struct {
+	Message string
+	Files   []string // 提交包含的文件
+	Options git.CommitOptions
+}
+
+// MemoryRepository is an in-memory git.Repository. Tests set up the working
+// tree with Change and inspect Branches and Pushed afterwards.
+type MemoryRepository struct {
+	Root string
+
+	Branch   string              // 当前分支
+	Branches map[string][]Commit // 分支的提交，最早的在前
+	Changes  []git.FileStatus    // 未提交的改动
+
+	RemoteURLs  map[string]string
+	RemoteHeads map[string]string   // 远程的默认分支，未设置时为 main
+	Pushed      map[string][]Commit // "remote/branch" 推送时的提交
+
+	// CommitErr is returned by Commit when set, e.g. a *git.HookError
+	CommitErr error
+}
+
+var _ git.Repository = (*MemoryRepository)(nil)
+
+// NewMemoryRepository returns a repository on main with one commit and an origin remote
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		Root:        "/repo",
+		Branch:      "main",
+		Branches:    map[string][]Commit{"main": {{Message: "init"}}},
+		RemoteURLs:  map[string]string{"origin": "git@github.com:owner/repo.git"},
+		RemoteHeads: map[string]string{},
+		Pushed:      map[string][]Commit{},
+	}
+}
+
+// Change adds an unstaged change to the working tree; new files are untracked
+func (r *MemoryRepository) Change(path string, untracked bool) {
+	f := git.FileStatus{Path: path, Index: ' ', Worktree: 'M'}
+	if untracked {
+		f.Index, f.Worktree = '?', '?'
+	}
+	r.Changes = append(r.Changes, f)
+}
+
+// Dir returns the root of the working tree
+func (r *MemoryRepository) Dir() string {
+	return r.Root
+}
+
+// Status lists the uncommitted changes
+func (r *MemoryRepository) Status() ([]git.FileStatus, error) {
+	return append([]git.FileStatus{}, r.Changes...), nil
+}
+
+// HasUncommittedChanges reports whether there are uncommitted changes
+func (r *MemoryRepository) HasUncommittedChanges() (bool, error) {
+	return len(r.Changes) > 0, nil
+}
+
+// CurrentBranch returns the current branch
+func (r *MemoryRepository) CurrentBranch() (string, error) {
+	return r.Branch, nil
+}
+
+// BranchExists checks if a local branch exists
+func (r *MemoryRepository) BranchExists(name string) bool {
+	_, ok := r.Branches[name]
+	return ok
+}
+
+// CreateBranch creates a branch at the current commit and checks it out
+func (r *MemoryRepository) CreateBranch(name string) error {
+	if r.BranchExists(name) {
+		return fmt.Errorf("failed to create branch %s: already exists", name)
+	}
+	r.Branches[name] = append([]Commit{}, r.Branches[r.Branch]...)
+	r.Branch = name
+	return nil
+}
+
+// CheckoutBranch checks out an existing branch
+func (r *MemoryRepository) CheckoutBranch(name string) error {
+	if !r.BranchExists(name) {
+		return fmt.Errorf("failed to checkout branch %s: no such branch", name)
+	}
+	r.Branch = name
+	return nil
+}
+
+// AddAll stages all changes
+func (r *MemoryRepository) AddAll() error {
+	for i := range r.Changes {
+		f := &r.Changes[i]
+		switch {
+		case f.IsUntracked():
+			f.Index = 'A'
+		case f.Worktree != ' ':
+			f.Index = f.Worktree
+		}
+		f.Worktree = ' '
+	}
+	return nil
+}
+
+// Commit commits the staged changes, or rewrites the last commit with Amend
+func (r *MemoryRepository) Commit(message string, opts git.CommitOptions) error {
+	if r.CommitErr != nil {
+		return r.CommitErr
+	}
+
+	staged := make([]string, 0)
+	rest := make([]git.FileStatus, 0)
+	for _, f := range r.Changes {
+		if f.IsStaged() {
+			staged = append(staged, f.Path)
+		}
+		if f.IsUnstaged() {
+			f.Index = ' '
+			rest = append(rest, f)
+		}
+	}
+	if len(staged) == 0 && !opts.Amend {
+		return fmt.Errorf("failed to commit: nothing to commit")
+	}
+	r.Changes = rest
+
+	commits := r.Branches[r.Branch]
+	commit := Commit{Message: message, Files: staged, Options: opts}
+	if opts.Fixup != "" {
+		commit.Message = "fixup! " + opts.Fixup
+	}
+	if opts.Amend && len(commits) > 0 {
+		last := commits[len(commits)-1]
+		if message == "" {
+			commit.Message = last.Message
+		}
+		commit.Files = append(append([]string{}, last.Files...), staged...)
+		commits = commits[:len(commits)-1]
+	}
+	r.Branches[r.Branch] = append(commits, commit)
+	return nil
+}
+
+// PushTo records the commits of branch as pushed to remote
+func (r *MemoryRepository) PushTo(remote, branch string) error {
+	if _, ok := r.RemoteURLs[remote]; !ok {
+		return fmt.Errorf("failed to push branch %s: no remote %s", branch, remote)
+	}
+	if !r.BranchExists(branch) {
+		return fmt.Errorf("failed to push branch %s: no such branch", branch)
+	}
+	r.Pushed[remote+"/"+branch] = append([]Commit{}, r.Branches[branch]...)
+	return nil
+}
+
+// Remotes lists the names of the remotes, sorted
+func (r *MemoryRepository) Remotes() ([]string, error) {
+	remotes := make([]string, 0, len(r.RemoteURLs))
+	for name := range r.RemoteURLs {
+		remotes = append(remotes, name)
+	}
+	sort.Strings(remotes)
+	return remotes, nil
+}
+
+// RemoteURL gets the URL of the given remote
+func (r *MemoryRepository) RemoteURL(remote string) (string, error) {
+	url, ok := r.RemoteURLs[remote]
+	if !ok {
+		return "", fmt.Errorf("failed to get URL of remote %s: no such remote", remote)
+	}
+	return url, nil
+}
+
+// DefaultBranch returns the default branch of the remote, main when not set
+func (r *MemoryRepository) DefaultBranch(remote string) (string, error) {
+	if branch := r.RemoteHeads[remote]; branch != "" {
+		return branch, nil
+	}
+	return "main", nil
+}
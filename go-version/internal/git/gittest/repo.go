@@ -0,0 +1,19 @@
+package gittest
+
+import (
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/git/testrepo"
+)
+
+// NewRepo opens a temporary on-disk repository created by testrepo.New
+func NewRepo(t *testing.T) *git.ExecRepository {
+	t.Helper()
+
+	repo, err := git.Open(testrepo.New(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
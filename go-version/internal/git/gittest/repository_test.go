@@ -0,0 +1,93 @@
+package gittest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/Wangggym/quick-workflow/internal/git/testrepo"
+)
+
+// testRepository runs the same branch, commit and push flow against a
+// Repository; change makes an untracked file "new.txt" appear
+func testRepository(t *testing.T, repo git.Repository, change func()) {
+	t.Helper()
+
+	if branch, err := repo.DefaultBranch("origin"); err != nil || branch != "main" {
+		t.Fatalf("DefaultBranch() = %q, %v, want main", branch, err)
+	}
+	if remotes, err := repo.Remotes(); err != nil || !reflect.DeepEqual(remotes, []string{"origin"}) {
+		t.Fatalf("Remotes() = %v, %v, want [origin]", remotes, err)
+	}
+	if _, err := repo.RemoteURL("origin"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.CreateBranch("feature"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateBranch("feature"); err == nil {
+		t.Error("CreateBranch() of an existing branch succeeded")
+	}
+	if branch, _ := repo.CurrentBranch(); branch != "feature" {
+		t.Errorf("CurrentBranch() = %q, want feature", branch)
+	}
+
+	if err := repo.Commit("empty", git.CommitOptions{}); err == nil {
+		t.Error("Commit() without changes succeeded")
+	}
+
+	change()
+	files, err := repo.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Path != "new.txt" || !files[0].IsUntracked() {
+		t.Fatalf("Status() = %+v, want untracked new.txt", files)
+	}
+
+	if err := repo.AddAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Commit("add new", git.CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if dirty, _ := repo.HasUncommittedChanges(); dirty {
+		t.Error("HasUncommittedChanges() = true after commit")
+	}
+
+	if err := repo.PushTo("origin", "feature"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.PushTo("nowhere", "feature"); err == nil {
+		t.Error("PushTo() an unknown remote succeeded")
+	}
+
+	if err := repo.CheckoutBranch("main"); err != nil {
+		t.Fatal(err)
+	}
+	if !repo.BranchExists("feature") || repo.BranchExists("missing") {
+		t.Error("BranchExists() is wrong")
+	}
+}
+
+func TestExecRepository(t *testing.T) {
+	repo := NewRepo(t)
+	testRepository(t, repo, func() { testrepo.WriteFile(t, repo.Dir(), "new.txt", "new\n") })
+
+	log := testrepo.Run(t, repo.Dir(), "log", "--format=%s", "origin/feature")
+	if got := strings.Split(strings.TrimSpace(log), "\n"); !reflect.DeepEqual(got, []string{"add new", "init"}) {
+		t.Errorf("pushed log = %q, want add new, init", log)
+	}
+}
+
+func TestMemoryRepository(t *testing.T) {
+	repo := NewMemoryRepository()
+	testRepository(t, repo, func() { repo.Change("new.txt", true) })
+
+	want := []Commit{{Message: "init"}, {Message: "add new", Files: []string{"new.txt"}}}
+	if got := repo.Pushed["origin/feature"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("pushed commits = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,146 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// HookError is returned when a git hook rejects a commit
+type HookError struct {
+	Hooks []string // 失败的钩子
+	Err   error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("rejected by git hook (%s): %v", strings.Join(e.Hooks, ", "), e.Err)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// HookExists reports whether an executable hook is installed, honoring core.hooksPath
+func HookExists(name string) bool {
+	return workingDir.hookExists(name)
+}
+
+func (r *ExecRepository) hookExists(name string) bool {
+	hookPath, err := r.gitPath("hooks/" + name)
+	if err != nil {
+		return false
+	}
+
+	info, err := os.Stat(hookPath)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return info.Mode()&0111 != 0
+}
+
+// RunHook runs a hook with its output shown to the user. When indexFile is set,
+// the hook sees it as the index instead of the real one.
+func RunHook(name, indexFile string) error {
+	cmd := exec.Command("git", "hook", "run", "--ignore-missing", name)
+	if indexFile != "" {
+		cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+indexFile)
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return &HookError{Hooks: []string{name}, Err: err}
+	}
+
+	return nil
+}
+
+// CopyIndex copies the index to a temporary file so that changes can be staged
+// without touching the real index. The caller removes the file.
+func CopyIndex() (string, error) {
+	indexPath, err := GitPath("index")
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp("", "qkflow-index-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	defer tmp.Close()
+
+	src, err := os.Open(indexPath)
+	if os.IsNotExist(err) {
+		// 还没有 index 时让 git 重新创建
+		os.Remove(tmp.Name())
+		return tmp.Name(), nil
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to read index: %w", err)
+	}
+	defer src.Close()
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to copy index: %w", err)
+	}
+
+	return tmp.Name(), nil
+}
+
+// StageInIndex stages the changes matching pathspecs (all changes when empty)
+// into the given index file
+func StageInIndex(indexFile string, pathspecs []string) error {
+	args := append([]string{"add", "--all", "--"}, pathspecs...)
+	if _, err := runGit([]string{"GIT_INDEX_FILE=" + indexFile}, args...); err != nil {
+		return fmt.Errorf("failed to stage changes: %w", err)
+	}
+	return nil
+}
+
+// failedHooks returns the hooks that exited with an error according to the
+// trace2 events of a git command. Events of git commands run by the hooks
+// themselves, whose session ids contain a '/', are ignored.
+func failedHooks(events []byte) []string {
+	hooks := make(map[int]string)
+	failed := make([]string, 0)
+	for _, line := range bytes.Split(events, []byte("\n")) {
+		var event struct {
+			Event      string `json:"event"`
+			SID        string `json:"sid"`
+			ChildID    int    `json:"child_id"`
+			ChildClass string `json:"child_class"`
+			HookName   string `json:"hook_name"`
+			Code       int    `json:"code"`
+		}
+		if json.Unmarshal(line, &event) != nil || strings.Contains(event.SID, "/") {
+			continue
+		}
+
+		switch {
+		case event.Event == "child_start" && event.ChildClass == "hook":
+			hooks[event.ChildID] = event.HookName
+		case event.Event == "child_exit" && event.Code != 0:
+			if name, ok := hooks[event.ChildID]; ok {
+				failed = append(failed, name)
+			}
+		}
+	}
+	return failed
+}
+
+// commitHooks returns the installed hooks that run on commit
+func (r *ExecRepository) commitHooks() []string {
+	hooks := make([]string, 0)
+	for _, name := range []string{"pre-commit", "prepare-commit-msg", "commit-msg"} {
+		if r.hookExists(name) {
+			hooks = append(hooks, name)
+		}
+	}
+	return hooks
+}
@@ -0,0 +1,79 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/git/testrepo"
+)
+
+func TestPreCommitHook(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+
+	// 暂存了 bad.txt 时拒绝提交
+	hook := "#!/bin/sh\nif git diff --cached --name-only | grep -q bad.txt; then echo 'bad.txt is staged'; exit 1; fi\n"
+	if err := os.WriteFile(".git/hooks/pre-commit", []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if !HookExists("pre-commit") {
+		t.Fatal("HookExists() = false, want true")
+	}
+
+	testrepo.WriteFile(t, ".", "bad.txt", "oops\n")
+
+	indexFile, err := CopyIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(indexFile)
+	if err := StageInIndex(indexFile, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := RunHook("pre-commit", indexFile); err == nil {
+		t.Fatal("RunHook() with bad.txt in the index should fail")
+	}
+	if HasStagedChanges() {
+		t.Fatal("the real index should be untouched")
+	}
+
+	testrepo.Run(t, ".", "add", "bad.txt")
+	var hookErr *HookError
+	if err := Commit("bad", CommitOptions{}); !errors.As(err, &hookErr) {
+		t.Fatalf("Commit() error = %v, want *HookError", err)
+	}
+	if err := Commit("bad", CommitOptions{NoVerify: true}); err != nil {
+		t.Fatalf("Commit() with noVerify: %v", err)
+	}
+}
+
+func TestCommitErrorWithHooks(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+
+	if err := os.WriteFile(".git/hooks/pre-commit", []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// 钩子都通过时，其他错误不算钩子拒绝
+	var hookErr *HookError
+	if err := Commit("empty", CommitOptions{}); err == nil || errors.As(err, &hookErr) {
+		t.Fatalf("Commit() without changes error = %v, want a plain error", err)
+	}
+
+	testrepo.WriteFile(t, ".", "a.txt", "two\n")
+	testrepo.Run(t, ".", "add", "a.txt")
+	testrepo.Run(t, ".", "config", "gpg.program", "false")
+	if err := Commit("signed", CommitOptions{Signing: Signing{Format: "gpg"}}); err == nil || errors.As(err, &hookErr) {
+		t.Fatalf("Commit() with failing signing error = %v, want a plain error", err)
+	}
+
+	if err := os.WriteFile(".git/hooks/commit-msg", []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Commit("rejected", CommitOptions{}); !errors.As(err, &hookErr) {
+		t.Fatalf("Commit() error = %v, want *HookError", err)
+	}
+	if len(hookErr.Hooks) != 1 || hookErr.Hooks[0] != "commit-msg" {
+		t.Errorf("HookError.Hooks = %v, want [commit-msg]", hookErr.Hooks)
+	}
+}
@@ -9,96 +9,72 @@ import (
 
 // CheckStatus checks if there are uncommitted changes
 func CheckStatus() (bool, error) {
-	cmd := exec.Command("git", "status", "--porcelain")
-	output, err := cmd.Output()
-	if err != nil {
-		return false, fmt.Errorf("failed to check git status: %w", err)
-	}
-
-	// 如果有输出，说明有未提交的更改
-	return len(output) > 0, nil
+	return workingDir.HasUncommittedChanges()
 }
 
 // GetCurrentBranch gets the current branch name
 func GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "branch", "--show-current")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to get current branch: %w", err)
-	}
-
-	return strings.TrimSpace(string(output)), nil
+	return workingDir.CurrentBranch()
 }
 
 // CreateBranch creates and checks out a new branch
 func CreateBranch(branchName string) error {
-	cmd := exec.Command("git", "checkout", "-b", branchName)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to create branch %s: %w\n%s", branchName, err, stderr.String())
-	}
-
-	return nil
+	return workingDir.CreateBranch(branchName)
 }
 
 // CheckoutBranch checks out an existing branch
 func CheckoutBranch(branchName string) error {
-	cmd := exec.Command("git", "checkout", branchName)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to checkout branch %s: %w\n%s", branchName, err, stderr.String())
-	}
-
-	return nil
+	return workingDir.CheckoutBranch(branchName)
 }
 
 // AddAll stages all changes
 func AddAll() error {
-	cmd := exec.Command("git", "add", "--all")
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to stage changes: %w\n%s", err, stderr.String())
-	}
-
-	return nil
+	return workingDir.AddAll()
 }
 
-// Commit creates a commit with the given message
-func Commit(message string) error {
-	cmd := exec.Command("git", "commit", "-m", message, "--no-verify")
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to commit: %w\n%s", err, stderr.String())
-	}
+// CommitOptions controls how Commit creates a commit
+type CommitOptions struct {
+	NoVerify bool    // 跳过 pre-commit 和 commit-msg 钩子
+	Signing  Signing // 签名方式，为空时沿用 git 配置
+	Amend    bool    // 修改上一个提交，没有 message 时保留原提交信息
+	Fixup    string  // 创建 "fixup! <subject>" 提交，之后由 autosquash 合并进该提交
+}
 
-	return nil
+// Commit creates a commit with the given message. The pre-commit and
+// commit-msg hooks run unless NoVerify is set; their output is shown to the
+// user and a rejection is returned as *HookError.
+func Commit(message string, opts CommitOptions) error {
+	return workingDir.Commit(message, opts)
 }
 
 // Push pushes the current branch to origin
 func Push(branchName string) error {
-	cmd := exec.Command("git", "push", "-u", "origin", branchName)
+	return PushTo("origin", branchName)
+}
+
+// PushTo pushes a branch to the given remote and sets it as upstream
+func PushTo(remote, branchName string) error {
+	return workingDir.PushTo(remote, branchName)
+}
+
+// DeleteBranch deletes a local branch (force delete)
+func DeleteBranch(branchName string) error {
+	// 使用 -D 强制删除，因为在 PR 合并后本地分支可能显示未合并
+	cmd := exec.Command("git", "branch", "-D", branchName)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to push branch %s: %w\n%s", branchName, err, stderr.String())
+		return fmt.Errorf("failed to delete branch %s: %w\n%s", branchName, err, stderr.String())
 	}
 
 	return nil
 }
 
-// DeleteBranch deletes a local branch (force delete)
-func DeleteBranch(branchName string) error {
-	// 使用 -D 强制删除，因为在 PR 合并后本地分支可能显示未合并
-	cmd := exec.Command("git", "branch", "-D", branchName)
+// DeleteMergedBranch deletes a local branch only when it is fully merged into
+// its upstream, or into HEAD when it has none
+func DeleteMergedBranch(branchName string) error {
+	cmd := exec.Command("git", "branch", "-d", branchName)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -111,7 +87,12 @@ func DeleteBranch(branchName string) error {
 
 // DeleteRemoteBranch deletes a remote branch
 func DeleteRemoteBranch(branchName string) error {
-	cmd := exec.Command("git", "push", "origin", "--delete", branchName)
+	return DeleteRemoteBranchFrom("origin", branchName)
+}
+
+// DeleteRemoteBranchFrom deletes a branch on the given remote
+func DeleteRemoteBranchFrom(remote, branchName string) error {
+	cmd := exec.Command("git", "push", remote, "--delete", branchName)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -124,13 +105,31 @@ func DeleteRemoteBranch(branchName string) error {
 
 // GetRemoteURL gets the remote URL
 func GetRemoteURL() (string, error) {
-	cmd := exec.Command("git", "remote", "get-url", "origin")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to get remote URL: %w", err)
+	return GetRemoteURLFor("origin")
+}
+
+// GetRemoteURLFor gets the URL of the given remote
+func GetRemoteURLFor(remote string) (string, error) {
+	return workingDir.RemoteURL(remote)
+}
+
+// RemoteExists checks if a remote with the given name is configured
+func RemoteExists(remote string) bool {
+	cmd := exec.Command("git", "remote", "get-url", remote)
+	return cmd.Run() == nil
+}
+
+// AddRemote adds a remote
+func AddRemote(name, url string) error {
+	cmd := exec.Command("git", "remote", "add", name, url)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to add remote %s: %w\n%s", name, err, stderr.String())
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	return nil
 }
 
 // SanitizeBranchName converts a string to a valid branch name
@@ -164,45 +163,130 @@ func IsGitRepository() bool {
 
 // GetDefaultBranch gets the default branch name (usually main or master)
 func GetDefaultBranch() (string, error) {
-	// 尝试从 origin/HEAD 获取默认分支
-	cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
+	return GetDefaultBranchOf("origin")
+}
+
+// GetDefaultBranchOf gets the default branch name of the given remote
+func GetDefaultBranchOf(remote string) (string, error) {
+	return workingDir.DefaultBranch(remote)
+}
+
+
+// ListRemoteBranches lists the branches of a remote known locally (without the remote prefix)
+func ListRemoteBranches(remote string) ([]string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname)", "refs/remotes/"+remote+"/")
 	output, err := cmd.Output()
-	if err == nil {
-		// 输出格式: refs/remotes/origin/main
-		branch := strings.TrimSpace(string(output))
-		branch = strings.TrimPrefix(branch, "refs/remotes/origin/")
-		if branch != "" {
-			return branch, nil
-		}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches of %s: %w", remote, err)
 	}
 
-	// 如果上面失败，尝试列出远程分支并检测 main 或 master
-	cmd = exec.Command("git", "remote", "show", "origin")
-	output, err = cmd.Output()
-	if err == nil {
-		lines := strings.Split(string(output), "\n")
-		for _, line := range lines {
-			if strings.Contains(line, "HEAD branch:") {
-				parts := strings.Split(line, ":")
-				if len(parts) == 2 {
-					branch := strings.TrimSpace(parts[1])
-					if branch != "" {
-						return branch, nil
-					}
-				}
-			}
+	prefix := "refs/remotes/" + remote + "/"
+	branches := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		branch := strings.TrimPrefix(line, prefix)
+		if branch == "" || branch == "HEAD" {
+			continue
 		}
+		branches = append(branches, branch)
+	}
+
+	return branches, nil
+}
+
+// LocalBranch is a local branch and the remote branch it tracks, if any
+type LocalBranch struct {
+	Name           string
+	SHA            string
+	UpstreamRemote string
+	UpstreamBranch string
+}
+
+// ListLocalBranches lists the local branches with their upstreams
+func ListLocalBranches() ([]LocalBranch, error) {
+	cmd := exec.Command("git", "for-each-ref",
+		"--format=%(refname:short)%00%(objectname)%00%(upstream:remotename)%00%(upstream:remoteref)", "refs/heads/")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local branches: %w", err)
 	}
 
-	// 最后的回退方案：检查 main 或 master 是否存在
-	for _, branch := range []string{"main", "master"} {
-		cmd := exec.Command("git", "show-ref", "--verify", "--quiet", "refs/remotes/origin/"+branch)
-		if cmd.Run() == nil {
-			return branch, nil
+	return parseLocalBranches(string(output)), nil
+}
+
+func parseLocalBranches(output string) []LocalBranch {
+	branches := make([]LocalBranch, 0)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 4 || fields[0] == "" {
+			continue
 		}
+		branches = append(branches, LocalBranch{
+			Name:           fields[0],
+			SHA:            fields[1],
+			UpstreamRemote: fields[2],
+			UpstreamBranch: strings.TrimPrefix(fields[3], "refs/heads/"),
+		})
+	}
+	return branches
+}
+
+// FetchBranch fetches a single branch from a remote, updating <remote>/<branch>
+func FetchBranch(remote, branchName string) error {
+	cmd := exec.Command("git", "fetch", remote, branchName)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to fetch %s from %s: %w\n%s", branchName, remote, err, stderr.String())
+	}
+
+	return nil
+}
+
+// CommitsBetween lists the commits reachable from head but not from base, one line each
+func CommitsBetween(base, head string) ([]string, error) {
+	cmd := exec.Command("git", "log", "--oneline", "--no-decorate", base+".."+head)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits between %s and %s: %w", base, head, err)
+	}
+
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" {
+		return []string{}, nil
+	}
+	return strings.Split(trimmed, "\n"), nil
+}
+
+// CommitSubjects lists the subjects of the commits in base..head, oldest first
+func CommitSubjects(base, head string) ([]string, error) {
+	cmd := exec.Command("git", "log", "--reverse", "--format=%s", base+".."+head)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits between %s and %s: %w", base, head, err)
 	}
 
-	// 默认返回 main
-	return "main", nil
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" {
+		return []string{}, nil
+	}
+	return strings.Split(trimmed, "\n"), nil
 }
 
+// RemoteBranchSHA returns the commit a branch points to on the remote, or "" if it doesn't exist
+func RemoteBranchSHA(remote, branchName string) (string, error) {
+	cmd := exec.Command("git", "ls-remote", "--heads", remote, "refs/heads/"+branchName)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to query %s: %w\n%s", remote, err, stderr.String())
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], nil
+}
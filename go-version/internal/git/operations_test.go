@@ -0,0 +1,44 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/git/testrepo"
+)
+
+func TestParseLocalBranches(t *testing.T) {
+	output := "main\x00aaa\x00origin\x00refs/heads/main\n" +
+		"pr-12\x00bbb\x00alice\x00refs/heads/fix/login\n" +
+		"wip\x00ccc\x00\x00\n"
+
+	want := []LocalBranch{
+		{Name: "main", SHA: "aaa", UpstreamRemote: "origin", UpstreamBranch: "main"},
+		{Name: "pr-12", SHA: "bbb", UpstreamRemote: "alice", UpstreamBranch: "fix/login"},
+		{Name: "wip", SHA: "ccc"},
+	}
+	if got := parseLocalBranches(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseLocalBranches() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDeleteMergedBranch(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+
+	testrepo.Run(t, ".", "branch", "merged")
+	testrepo.Run(t, ".", "checkout", "-q", "-b", "wip")
+	testrepo.WriteFile(t, ".", "b.txt", "wip\n")
+	testrepo.Run(t, ".", "add", "b.txt")
+	testrepo.Run(t, ".", "commit", "-q", "-m", "wip")
+	testrepo.Run(t, ".", "checkout", "-q", "main")
+
+	if err := DeleteMergedBranch("merged"); err != nil {
+		t.Errorf("DeleteMergedBranch() of a merged branch: %v", err)
+	}
+	if err := DeleteMergedBranch("wip"); err == nil {
+		t.Error("DeleteMergedBranch() deleted a branch with unmerged commits")
+	}
+	if !BranchExists("wip") {
+		t.Error("wip was deleted")
+	}
+}
@@ -0,0 +1,192 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Fetch fetches a remote and prunes deleted remote branches
+func Fetch(remote string) error {
+	cmd := exec.Command("git", "fetch", "--prune", remote)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w\n%s", remote, err, stderr.String())
+	}
+
+	return nil
+}
+
+// IsAncestor reports whether commit ancestor is reachable from descendant
+func IsAncestor(ancestor, descendant string) bool {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", ancestor, descendant)
+	return cmd.Run() == nil
+}
+
+// MergeBase returns the best common ancestor of two commits
+func MergeBase(a, b string) (string, error) {
+	cmd := exec.Command("git", "merge-base", a, b)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find merge base of %s and %s: %w", a, b, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RebaseOnto replays the commits of branch after upstream onto newBase.
+// The branch is checked out; on conflict the rebase is left in progress.
+func RebaseOnto(newBase, upstream, branch string) error {
+	cmd := exec.Command("git", "rebase", "--onto", newBase, upstream, branch)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to rebase %s onto %s: %w\n%s", branch, newBase, err, stderr.String())
+	}
+
+	return nil
+}
+
+// RebaseInProgress reports whether a rebase is in progress
+func RebaseInProgress() bool {
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		cmd := exec.Command("git", "rev-parse", "--git-path", dir)
+		output, err := cmd.Output()
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(strings.TrimSpace(string(output))); err == nil && info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// PushForceWithLeaseTo pushes a rewritten branch to remote. The push is refused
+// unless the remote branch is still at expect, or at its remote-tracking ref
+// when expect is empty.
+func PushForceWithLeaseTo(remote, branchName, expect string) error {
+	lease := "--force-with-lease"
+	if expect != "" {
+		lease = fmt.Sprintf("--force-with-lease=%s:%s", branchName, expect)
+	}
+
+	cmd := exec.Command("git", "push", lease, remote, branchName)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to push branch %s: %w\n%s", branchName, err, stderr.String())
+	}
+
+	return nil
+}
+
+// RebaseAutosquash squashes fixup! and squash! commits after upstream into the
+// commits they fix, without opening an editor. Local changes are stashed
+// during the rebase; on conflict the rebase is left in progress. Rewritten
+// commits are signed when signing is enabled.
+func RebaseAutosquash(upstream string, signing Signing) error {
+	args := append(signing.args(), "rebase", "--interactive", "--autosquash", "--autostash")
+	if signing.Enabled() {
+		args = append(args, "--gpg-sign")
+	}
+	cmd := exec.Command("git", append(args, upstream)...)
+	// 不打开编辑器，直接使用 autosquash 排好的顺序
+	cmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=true")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to autosquash onto %s: %w\n%s", upstream, err, stderr.String())
+	}
+
+	return nil
+}
+
+// RebaseAutostash rebases the current branch onto upstream. Local changes are
+// stashed during the rebase; on conflict the rebase is left in progress.
+// Rebased commits are signed when signing is enabled.
+func RebaseAutostash(upstream string, signing Signing) error {
+	args := append(signing.args(), "rebase", "--autostash")
+	if signing.Enabled() {
+		args = append(args, "--gpg-sign")
+	}
+	cmd := exec.Command("git", append(args, upstream)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to rebase onto %s: %w\n%s", upstream, err, stderr.String())
+	}
+
+	return nil
+}
+
+// MergeAutostash merges upstream into the current branch. Local changes are
+// stashed during the merge; on conflict the merge is left in progress and the
+// stash is applied when it is committed or aborted.
+func MergeAutostash(upstream string, signing Signing) error {
+	args := append(signing.args(), "merge", "--autostash", "--no-edit")
+	if signing.Enabled() {
+		args = append(args, "--gpg-sign")
+	}
+	cmd := exec.Command("git", append(args, upstream)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to merge %s: %w\n%s", upstream, err, stderr.String())
+	}
+
+	return nil
+}
+
+// MergeInProgress reports whether a merge is in progress
+func MergeInProgress() bool {
+	cmd := exec.Command("git", "rev-parse", "-q", "--verify", "MERGE_HEAD")
+	return cmd.Run() == nil
+}
+
+// MergeConflicts does a trial merge of head into base, without touching the
+// working tree or the index, and returns the files that would conflict.
+// It needs git 2.38 or later.
+func MergeConflicts(base, head string) ([]string, error) {
+	cmd := exec.Command("git", "merge-tree", "--write-tree", "--name-only", "--no-messages", base, head)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// 退出码 1 表示有冲突，其他非零退出码是错误
+	output, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if err != nil && (!errors.As(err, &exitErr) || exitErr.ExitCode() != 1) {
+		return nil, fmt.Errorf("failed to merge %s into %s: %w\n%s", head, base, err, stderr.String())
+	}
+
+	return parseMergeTree(string(output)), nil
+}
+
+// parseMergeTree parses the output of git merge-tree --write-tree --name-only:
+// the tree of the result, then the conflicting files
+func parseMergeTree(output string) []string {
+	files := make([]string, 0)
+	seen := make(map[string]bool)
+	lines := strings.Split(output, "\n")
+	for _, line := range lines[1:] {
+		// 空行之后是说明信息
+		if line == "" {
+			break
+		}
+		if !seen[line] {
+			seen[line] = true
+			files = append(files, line)
+		}
+	}
+	return files
+}
@@ -0,0 +1,146 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/git/testrepo"
+)
+
+func TestFixupAutosquash(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+	base := strings.TrimSpace(testrepo.Run(t, ".", "rev-parse", "HEAD"))
+
+	testrepo.WriteFile(t, ".", "b.txt", "b\n")
+	testrepo.Run(t, ".", "add", "b.txt")
+	if err := Commit("add b", CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	target := strings.TrimSpace(testrepo.Run(t, ".", "rev-parse", "HEAD"))
+
+	testrepo.WriteFile(t, ".", "c.txt", "c\n")
+	testrepo.Run(t, ".", "add", "c.txt")
+	if err := Commit("add c", CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	testrepo.WriteFile(t, ".", "b.txt", "b fixed\n")
+	testrepo.Run(t, ".", "add", "b.txt")
+	if err := Commit("", CommitOptions{Fixup: target}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 未提交的改动在 autosquash 期间被暂存并恢复
+	testrepo.WriteFile(t, ".", "a.txt", "dirty\n")
+
+	if err := RebaseAutosquash(base, Signing{}); err != nil {
+		t.Fatal(err)
+	}
+
+	subjects, err := CommitSubjects(base, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(subjects, ",") != "add b,add c" {
+		t.Errorf("subjects after autosquash = %v, want [add b add c]", subjects)
+	}
+	if got := testrepo.Run(t, ".", "show", "HEAD~1:b.txt"); got != "b fixed\n" {
+		t.Errorf("b.txt in the fixed commit = %q, want the fixup content", got)
+	}
+	if got := testrepo.Run(t, ".", "diff", "--name-only"); strings.TrimSpace(got) != "a.txt" {
+		t.Errorf("local changes after autosquash = %q, want a.txt", got)
+	}
+}
+
+func TestAmendKeepsMessage(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+
+	testrepo.WriteFile(t, ".", "a.txt", "amended\n")
+	testrepo.Run(t, ".", "add", "a.txt")
+	if err := Commit("", CommitOptions{Amend: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.TrimSpace(testrepo.Run(t, ".", "log", "--format=%s")); got != "init" {
+		t.Errorf("log after amend = %q, want a single init commit", got)
+	}
+}
+
+func TestSyncAutostash(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+	testrepo.Run(t, ".", "checkout", "-b", "feature")
+	testrepo.WriteFile(t, ".", "b.txt", "b\n")
+	testrepo.Run(t, ".", "add", "b.txt")
+	testrepo.Run(t, ".", "commit", "-m", "add b")
+
+	testrepo.Run(t, ".", "checkout", "main")
+	testrepo.WriteFile(t, ".", "c.txt", "c\n")
+	testrepo.Run(t, ".", "add", "c.txt")
+	testrepo.Run(t, ".", "commit", "-m", "add c")
+	testrepo.Run(t, ".", "checkout", "feature")
+
+	testrepo.WriteFile(t, ".", "a.txt", "dirty\n")
+	if err := RebaseAutostash("main", Signing{}); err != nil {
+		t.Fatal(err)
+	}
+	if !IsAncestor("main", "HEAD") {
+		t.Error("feature is not based on main after the rebase")
+	}
+	if got := testrepo.Run(t, ".", "diff", "--name-only"); strings.TrimSpace(got) != "a.txt" {
+		t.Errorf("local changes after rebase = %q, want a.txt", got)
+	}
+
+	// 冲突时 merge 保持进行中
+	testrepo.Run(t, ".", "checkout", "main")
+	testrepo.WriteFile(t, ".", "b.txt", "main\n")
+	testrepo.Run(t, ".", "add", "b.txt")
+	testrepo.Run(t, ".", "commit", "-m", "b on main")
+	testrepo.Run(t, ".", "checkout", "feature")
+
+	if err := MergeAutostash("main", Signing{}); err == nil {
+		t.Fatal("merge with a conflict succeeded")
+	}
+	if !MergeInProgress() {
+		t.Error("merge is not in progress after a conflict")
+	}
+	testrepo.Run(t, ".", "merge", "--abort")
+	if got := testrepo.Run(t, ".", "diff", "--name-only"); strings.TrimSpace(got) != "a.txt" {
+		t.Errorf("local changes after merge --abort = %q, want a.txt", got)
+	}
+}
+
+func TestMergeConflicts(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+	testrepo.Run(t, ".", "checkout", "-q", "-b", "feature")
+	testrepo.WriteFile(t, ".", "a.txt", "feature\n")
+	testrepo.WriteFile(t, ".", "b.txt", "b\n")
+	testrepo.Run(t, ".", "add", "-A")
+	testrepo.Run(t, ".", "commit", "-q", "-m", "feature")
+
+	testrepo.Run(t, ".", "checkout", "-q", "main")
+	testrepo.WriteFile(t, ".", "c.txt", "c\n")
+	testrepo.Run(t, ".", "add", "c.txt")
+	testrepo.Run(t, ".", "commit", "-q", "-m", "clean")
+
+	files, err := MergeConflicts("main", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("MergeConflicts() = %v, want none", files)
+	}
+
+	testrepo.WriteFile(t, ".", "a.txt", "main\n")
+	testrepo.Run(t, ".", "commit", "-q", "-am", "conflict")
+
+	files, err = MergeConflicts("main", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(files, ",") != "a.txt" {
+		t.Errorf("MergeConflicts() = %v, want [a.txt]", files)
+	}
+	if status := testrepo.Run(t, ".", "status", "--porcelain"); status != "" {
+		t.Errorf("trial merge changed the working tree: %q", status)
+	}
+}
@@ -0,0 +1,293 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Repository covers the basic operations on a git repository: status,
+// branches, staging, commit, push and remotes. Open returns one for a
+// directory; everything else is only available as package-level functions
+// acting on the process working directory. ExecRepository is the only
+// implementation backed by git (there is no pure-Go backend);
+// gittest.MemoryRepository is an in-memory fake for code that only uses the
+// interface.
+type Repository interface {
+	// Dir returns the root of the working tree
+	Dir() string
+
+	Status() ([]FileStatus, error)
+	HasUncommittedChanges() (bool, error)
+
+	CurrentBranch() (string, error)
+	BranchExists(name string) bool
+	CreateBranch(name string) error
+	CheckoutBranch(name string) error
+
+	AddAll() error
+	Commit(message string, opts CommitOptions) error
+	PushTo(remote, branch string) error
+
+	Remotes() ([]string, error)
+	RemoteURL(remote string) (string, error)
+	DefaultBranch(remote string) (string, error)
+}
+
+// ExecRepository is a Repository backed by the git binary
+type ExecRepository struct {
+	dir string // 为空时使用进程的工作目录
+}
+
+var _ Repository = (*ExecRepository)(nil)
+
+// workingDir is the repository of the process working directory
+var workingDir = &ExecRepository{}
+
+// WorkingDir returns the repository of the process working directory
+func WorkingDir() *ExecRepository {
+	return workingDir
+}
+
+// Open returns the repository containing dir
+func Open(dir string) (*ExecRepository, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+
+	top, err := (&ExecRepository{dir: abs}).topLevel()
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a git repository: %w", dir, err)
+	}
+
+	return &ExecRepository{dir: top}, nil
+}
+
+// command prepares a git command running in the repository
+func (r *ExecRepository) command(args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	return cmd
+}
+
+// run runs a git command, returning its stderr on failure
+func (r *ExecRepository) run(args ...string) error {
+	cmd := r.command(args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w\n%s", err, stderr.String())
+	}
+	return nil
+}
+
+// Dir returns the root of the working tree
+func (r *ExecRepository) Dir() string {
+	if r.dir != "" {
+		return r.dir
+	}
+	top, _ := r.topLevel()
+	return top
+}
+
+func (r *ExecRepository) topLevel() (string, error) {
+	output, err := r.command("rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find repository root: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitPath resolves a path inside the git directory of the worktree
+func (r *ExecRepository) gitPath(name string) (string, error) {
+	output, err := r.command("rev-parse", "--path-format=absolute", "--git-path", name).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve git path %s: %w", name, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// HasUncommittedChanges checks if there are uncommitted changes
+func (r *ExecRepository) HasUncommittedChanges() (bool, error) {
+	output, err := r.command("status", "--porcelain").Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to check git status: %w", err)
+	}
+
+	// 如果有输出，说明有未提交的更改
+	return len(output) > 0, nil
+}
+
+// CurrentBranch gets the current branch name
+func (r *ExecRepository) CurrentBranch() (string, error) {
+	output, err := r.command("branch", "--show-current").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// BranchExists checks if a local branch exists
+func (r *ExecRepository) BranchExists(name string) bool {
+	return r.command("show-ref", "--verify", "--quiet", "refs/heads/"+name).Run() == nil
+}
+
+// CreateBranch creates and checks out a new branch
+func (r *ExecRepository) CreateBranch(name string) error {
+	if err := r.run("checkout", "-b", name); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", name, err)
+	}
+	return nil
+}
+
+// CheckoutBranch checks out an existing branch
+func (r *ExecRepository) CheckoutBranch(name string) error {
+	if err := r.run("checkout", name); err != nil {
+		return fmt.Errorf("failed to checkout branch %s: %w", name, err)
+	}
+	return nil
+}
+
+// AddAll stages all changes
+func (r *ExecRepository) AddAll() error {
+	if err := r.run("add", "--all"); err != nil {
+		return fmt.Errorf("failed to stage changes: %w", err)
+	}
+	return nil
+}
+
+// Commit creates a commit with the given message. The pre-commit and
+// commit-msg hooks run unless NoVerify is set; their output is shown to the
+// user and a rejection is returned as *HookError.
+func (r *ExecRepository) Commit(message string, opts CommitOptions) error {
+	args := append(opts.Signing.args(), "commit")
+	switch {
+	case opts.Fixup != "":
+		args = append(args, "--fixup="+opts.Fixup)
+	case opts.Amend && message == "":
+		args = append(args, "--amend", "--no-edit")
+	case opts.Amend:
+		args = append(args, "--amend", "-m", message)
+	default:
+		args = append(args, "-m", message)
+	}
+	if opts.Signing.Enabled() {
+		args = append(args, "-S")
+	}
+	if opts.NoVerify {
+		args = append(args, "--no-verify")
+	}
+
+	cmd := r.command(args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	hooks := []string{}
+	if !opts.NoVerify {
+		hooks = r.commitHooks()
+	}
+	trace := ""
+	if len(hooks) > 0 {
+		// 钩子的输出（如 lint 结果）直接显示
+		cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+
+		// trace2 事件记录每个钩子的退出码，用来区分钩子失败和签名失败等其他错误
+		if f, err := os.CreateTemp("", "qkflow-trace-*"); err == nil {
+			trace = f.Name()
+			f.Close()
+			defer os.Remove(trace)
+			cmd.Env = append(os.Environ(), "GIT_TRACE2_EVENT="+trace)
+		}
+	}
+
+	if err := cmd.Run(); err != nil {
+		if trace != "" {
+			if events, readErr := os.ReadFile(trace); readErr == nil {
+				if failed := failedHooks(events); len(failed) > 0 {
+					return &HookError{Hooks: failed, Err: err}
+				}
+			}
+		}
+		return fmt.Errorf("failed to commit: %w\n%s", err, stderr.String())
+	}
+
+	return nil
+}
+
+// PushTo pushes a branch to the given remote and sets it as upstream
+func (r *ExecRepository) PushTo(remote, branch string) error {
+	if err := r.run("push", "-u", remote, branch); err != nil {
+		return fmt.Errorf("failed to push branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// Remotes lists the names of the configured remotes
+func (r *ExecRepository) Remotes() ([]string, error) {
+	output, err := r.command("remote").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+
+	return strings.Fields(string(output)), nil
+}
+
+// RemoteURL gets the URL of the given remote
+func (r *ExecRepository) RemoteURL(remote string) (string, error) {
+	output, err := r.command("remote", "get-url", remote).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get URL of remote %s: %w", remote, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DefaultBranch gets the default branch name of the given remote
+func (r *ExecRepository) DefaultBranch(remote string) (string, error) {
+	// 尝试从 <remote>/HEAD 获取默认分支
+	output, err := r.command("symbolic-ref", "refs/remotes/"+remote+"/HEAD").Output()
+	if err == nil {
+		// 输出格式: refs/remotes/origin/main
+		branch := strings.TrimSpace(string(output))
+		branch = strings.TrimPrefix(branch, "refs/remotes/"+remote+"/")
+		if branch != "" {
+			return branch, nil
+		}
+	}
+
+	// 如果上面失败，尝试列出远程分支并检测 main 或 master
+	output, err = r.command("remote", "show", remote).Output()
+	if err == nil {
+		lines := strings.Split(string(output), "\n")
+		for _, line := range lines {
+			if strings.Contains(line, "HEAD branch:") {
+				parts := strings.Split(line, ":")
+				if len(parts) == 2 {
+					branch := strings.TrimSpace(parts[1])
+					if branch != "" {
+						return branch, nil
+					}
+				}
+			}
+		}
+	}
+
+	// 最后的回退方案：检查 main 或 master 是否存在
+	for _, branch := range []string{"main", "master"} {
+		if r.command("show-ref", "--verify", "--quiet", "refs/remotes/"+remote+"/"+branch).Run() == nil {
+			return branch, nil
+		}
+	}
+
+	// 默认返回 main
+	return "main", nil
+}
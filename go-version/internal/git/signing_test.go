@@ -0,0 +1,72 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/git/testrepo"
+)
+
+func TestParseUnsigned(t *testing.T) {
+	output := `commit abc1234abc1234abc1234abc1234abc1234abc12
+tree 1111111111111111111111111111111111111111
+author a <a@example.com> 1700000000 +0000
+committer a <a@example.com> 1700000000 +0000
+gpgsig -----BEGIN SSH SIGNATURE-----
+ U1NIU0lH
+ -----END SSH SIGNATURE-----
+
+    signed
+
+    gpgsig in the body doesn't count
+commit def5678def5678def5678def5678def5678def56
+tree 2222222222222222222222222222222222222222
+author a <a@example.com> 1700000000 +0000
+committer a <a@example.com> 1700000000 +0000
+
+    unsigned
+`
+
+	got := parseUnsigned(output)
+	want := []string{"def5678 unsigned"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseUnsigned() = %v, want %v", got, want)
+	}
+}
+
+func TestCommitSSHSigning(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+	dir := testrepo.New(t)
+	testrepo.Chdir(t, dir)
+	base := strings.TrimSpace(testrepo.Run(t, ".", "rev-parse", "HEAD"))
+
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	if output, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v\n%s", err, output)
+	}
+
+	testrepo.WriteFile(t, dir, "a.txt", "two\n")
+	testrepo.Run(t, ".", "add", "a.txt")
+	if err := Commit("unsigned", CommitOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	testrepo.WriteFile(t, dir, "a.txt", "three\n")
+	testrepo.Run(t, ".", "add", "a.txt")
+	if err := Commit("signed", CommitOptions{Signing: Signing{Format: SigningSSH, Key: key + ".pub"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	unsigned, err := UnsignedCommits(base, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unsigned) != 1 || !strings.HasSuffix(unsigned[0], " unsigned") {
+		t.Errorf("UnsignedCommits() = %v, want only the unsigned commit", unsigned)
+	}
+}
@@ -0,0 +1,251 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SnapshotRefPrefix is where recovery snapshots are kept
+const SnapshotRefPrefix = "refs/qkflow/recovery/"
+
+// Snapshot is a saved copy of HEAD, the index and the working tree (including
+// untracked files). It is stored as a commit whose tree is the working tree,
+// with HEAD and a commit of the index as parents, so that git keeps the
+// objects alive as long as the ref exists.
+type Snapshot struct {
+	Ref          string
+	Reason       string
+	Branch       string
+	Head         string
+	IndexTree    string
+	WorktreeTree string
+	CreatedAt    time.Time
+}
+
+// Name returns the snapshot ref without the recovery prefix
+func (s *Snapshot) Name() string {
+	return strings.TrimPrefix(s.Ref, SnapshotRefPrefix)
+}
+
+// CreateSnapshot records the current HEAD, index and working tree under a new recovery ref
+func CreateSnapshot(reason string) (*Snapshot, error) {
+	head, err := RevParse("HEAD")
+	if err != nil {
+		return nil, err
+	}
+	branch, _ := GetCurrentBranch()
+
+	indexTree, err := runGit(nil, "write-tree")
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot index: %w", err)
+	}
+
+	// 用临时 index 记录工作区（含未跟踪文件），不影响真实的暂存区
+	tmpIndex, err := GitPath("qkflow/snapshot-index")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(tmpIndex), 0755); err != nil {
+		return nil, fmt.Errorf("failed to snapshot working tree: %w", err)
+	}
+	defer os.Remove(tmpIndex)
+
+	env := []string{"GIT_INDEX_FILE=" + tmpIndex}
+	if _, err := runGit(env, "read-tree", indexTree); err != nil {
+		return nil, fmt.Errorf("failed to snapshot working tree: %w", err)
+	}
+	if _, err := runGit(env, "add", "--all"); err != nil {
+		return nil, fmt.Errorf("failed to snapshot working tree: %w", err)
+	}
+	worktreeTree, err := runGit(env, "write-tree")
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot working tree: %w", err)
+	}
+
+	indexCommit, err := commitTree(indexTree, "qkflow snapshot index", head)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("qkflow snapshot: %s\n\nbranch: %s\nindex: %s\n", reason, branch, indexTree)
+	snapshotCommit, err := commitTree(worktreeTree, message, head, indexCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ref := SnapshotRefPrefix + now.Format("20060102-150405") + "-" + snapshotCommit[:7]
+	if _, err := runGit(nil, "update-ref", ref, snapshotCommit); err != nil {
+		return nil, fmt.Errorf("failed to save recovery ref: %w", err)
+	}
+
+	return &Snapshot{
+		Ref:          ref,
+		Reason:       reason,
+		Branch:       branch,
+		Head:         head,
+		IndexTree:    indexTree,
+		WorktreeTree: worktreeTree,
+		CreatedAt:    now,
+	}, nil
+}
+
+// LoadSnapshot reads the snapshot stored at ref
+func LoadSnapshot(ref string) (*Snapshot, error) {
+	data, err := runGit(nil, "cat-file", "commit", ref)
+	if err != nil {
+		return nil, fmt.Errorf("recovery ref %s not found: %w", ref, err)
+	}
+	return parseSnapshot(ref, data)
+}
+
+// ListSnapshots returns the saved snapshots, newest first
+func ListSnapshots() ([]Snapshot, error) {
+	output, err := runGit(nil, "for-each-ref", "--format=%(refname)", SnapshotRefPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recovery refs: %w", err)
+	}
+
+	snapshots := make([]Snapshot, 0)
+	for _, ref := range strings.Fields(output) {
+		s, err := LoadSnapshot(ref)
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, *s)
+	}
+
+	sort.SliceStable(snapshots, func(i, k int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[k].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+// DeleteSnapshot removes a recovery ref
+func DeleteSnapshot(ref string) error {
+	if _, err := runGit(nil, "update-ref", "-d", ref); err != nil {
+		return fmt.Errorf("failed to delete recovery ref %s: %w", ref, err)
+	}
+	return nil
+}
+
+// Restore brings back the working tree and index of the snapshot on top of the
+// current HEAD, which should be the snapshot's HEAD. Files that were untracked
+// become untracked again and partially staged changes are restored exactly.
+func (s *Snapshot) Restore() error {
+	if _, err := runGit(nil, "read-tree", "--reset", "-u", s.WorktreeTree); err != nil {
+		return fmt.Errorf("failed to restore working tree: %w", err)
+	}
+	if _, err := runGit(nil, "read-tree", s.IndexTree); err != nil {
+		return fmt.Errorf("failed to restore index: %w", err)
+	}
+
+	// 刷新 index 中的文件状态，避免 status 显示假的改动
+	runGit(nil, "update-index", "-q", "--refresh")
+	return nil
+}
+
+// ResetSoft points the current branch at sha, keeping the index and the working tree
+func ResetSoft(sha string) error {
+	if _, err := runGit(nil, "reset", "--soft", sha); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", sha, err)
+	}
+	return nil
+}
+
+// ForceCheckout checks out a branch, discarding local changes to tracked files
+func ForceCheckout(branchName string) error {
+	if _, err := runGit(nil, "checkout", "-f", branchName); err != nil {
+		return fmt.Errorf("failed to checkout branch %s: %w", branchName, err)
+	}
+	return nil
+}
+
+// parseSnapshot parses the raw snapshot commit written by CreateSnapshot
+func parseSnapshot(ref, data string) (*Snapshot, error) {
+	header, message, _ := strings.Cut(data, "\n\n")
+
+	s := &Snapshot{Ref: ref}
+	parents := make([]string, 0, 2)
+	for _, line := range strings.Split(header, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "tree":
+			s.WorktreeTree = value
+		case "parent":
+			parents = append(parents, value)
+		case "committer":
+			// committer Name <email> 1700000000 +0800
+			fields := strings.Fields(value)
+			if len(fields) >= 2 {
+				if ts, err := strconv.ParseInt(fields[len(fields)-2], 10, 64); err == nil {
+					s.CreatedAt = time.Unix(ts, 0)
+				}
+			}
+		}
+	}
+
+	lines := strings.Split(message, "\n")
+	if !strings.HasPrefix(lines[0], "qkflow snapshot: ") || len(parents) != 2 {
+		return nil, fmt.Errorf("%s is not a qkflow snapshot", ref)
+	}
+	s.Reason = strings.TrimPrefix(lines[0], "qkflow snapshot: ")
+	s.Head = parents[0]
+
+	for _, line := range lines[1:] {
+		key, value, _ := strings.Cut(line, ": ")
+		switch key {
+		case "branch":
+			s.Branch = value
+		case "index":
+			s.IndexTree = value
+		}
+	}
+	if s.IndexTree == "" {
+		return nil, fmt.Errorf("%s has no index tree", ref)
+	}
+
+	return s, nil
+}
+
+func commitTree(tree, message string, parents ...string) (string, error) {
+	args := []string{"commit-tree", tree, "-m", message}
+	for _, p := range parents {
+		args = append(args, "-p", p)
+	}
+
+	// 快照提交使用固定身份，未配置 user.name 时也能创建
+	env := []string{
+		"GIT_AUTHOR_NAME=qkflow", "GIT_AUTHOR_EMAIL=qkflow@localhost",
+		"GIT_COMMITTER_NAME=qkflow", "GIT_COMMITTER_EMAIL=qkflow@localhost",
+	}
+	sha, err := runGit(env, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot commit: %w", err)
+	}
+	return sha, nil
+}
+
+// runGit runs git with extra environment variables and returns the trimmed stdout
+func runGit(env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w\n%s", err, stderr.String())
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,64 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/git/testrepo"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+
+	// 部分暂存：暂存区与工作区各有不同的改动，外加一个未跟踪文件
+	testrepo.WriteFile(t, ".", "a.txt", "one\nstaged\n")
+	testrepo.Run(t, ".", "add", "a.txt")
+	testrepo.WriteFile(t, ".", "a.txt", "one\nstaged\nunstaged\n")
+	testrepo.WriteFile(t, ".", "new/untracked.txt", "untracked\n")
+
+	wantStatus := testrepo.Run(t, ".", "status", "--porcelain", "--untracked-files=all")
+	wantCached := testrepo.Run(t, ".", "diff", "--cached")
+	wantDiff := testrepo.Run(t, ".", "diff")
+
+	s, err := CreateSnapshot("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 模拟 pr create：提交到新分支后回到 main
+	testrepo.Run(t, ".", "checkout", "-q", "-b", "feature")
+	testrepo.Run(t, ".", "add", "--all")
+	testrepo.Run(t, ".", "commit", "-q", "-m", "work")
+	if err := ForceCheckout("main"); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadSnapshot(s.Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Branch != "main" || loaded.Head != s.Head || loaded.IndexTree != s.IndexTree || loaded.Reason != "test" {
+		t.Errorf("LoadSnapshot() = %+v, want %+v", loaded, s)
+	}
+
+	if err := loaded.Restore(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := testrepo.Run(t, ".", "status", "--porcelain", "--untracked-files=all"); got != wantStatus {
+		t.Errorf("status = %q, want %q", got, wantStatus)
+	}
+	if got := testrepo.Run(t, ".", "diff", "--cached"); got != wantCached {
+		t.Errorf("staged diff = %q, want %q", got, wantCached)
+	}
+	if got := testrepo.Run(t, ".", "diff"); got != wantDiff {
+		t.Errorf("unstaged diff = %q, want %q", got, wantDiff)
+	}
+
+	snapshots, err := ListSnapshots()
+	if err != nil || len(snapshots) != 1 || snapshots[0].Ref != s.Ref {
+		t.Fatalf("ListSnapshots() = %v, %v", snapshots, err)
+	}
+	if err := DeleteSnapshot(s.Ref); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,23 @@
+package git
+
+import "fmt"
+
+// StashPush stashes the local changes, including untracked files, and reports
+// whether there was anything to stash
+func StashPush(message string) (bool, error) {
+	before, _ := RevParse("refs/stash")
+	if _, err := runGit(nil, "stash", "push", "--include-untracked", "-m", message); err != nil {
+		return false, fmt.Errorf("failed to stash local changes: %w", err)
+	}
+	// 没有改动时 git 不创建 stash，不能 pop 之前的 stash
+	after, _ := RevParse("refs/stash")
+	return after != "" && after != before, nil
+}
+
+// StashPop restores the last stash, including which changes were staged
+func StashPop() error {
+	if _, err := runGit(nil, "stash", "pop", "--index"); err != nil {
+		return fmt.Errorf("failed to restore stashed changes: %w", err)
+	}
+	return nil
+}
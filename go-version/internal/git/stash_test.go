@@ -0,0 +1,45 @@
+package git
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/git/testrepo"
+)
+
+func TestStashPushPop(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+
+	stashed, err := StashPush("nothing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stashed {
+		t.Fatal("StashPush() without changes should stash nothing")
+	}
+
+	testrepo.WriteFile(t, ".", "a.txt", "staged\n")
+	testrepo.Run(t, ".", "add", "a.txt")
+	testrepo.WriteFile(t, ".", "new.txt", "untracked\n")
+
+	stashed, err = StashPush("checks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stashed {
+		t.Fatal("StashPush() should stash the changes")
+	}
+	if _, err := os.Stat("new.txt"); !os.IsNotExist(err) {
+		t.Error("untracked files should be stashed")
+	}
+
+	if err := StashPop(); err != nil {
+		t.Fatal(err)
+	}
+	if !HasStagedChanges() {
+		t.Error("StashPop() should restore the staged changes")
+	}
+	if data, err := os.ReadFile("new.txt"); err != nil || string(data) != "untracked\n" {
+		t.Errorf("new.txt = %q, %v, want restored", data, err)
+	}
+}
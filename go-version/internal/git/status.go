@@ -0,0 +1,313 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FileStatus is a changed file as reported by git status --porcelain
+type FileStatus struct {
+	Path     string
+	OrigPath string // 重命名前的路径
+	Index    byte   // 暂存区状态 (X)
+	Worktree byte   // 工作区状态 (Y)
+
+	Added   int   // 相对 HEAD 新增的行数，二进制文件为 -1
+	Deleted int   // 相对 HEAD 删除的行数，二进制文件为 -1
+	Size    int64 // 未跟踪文件的大小
+}
+
+// IsUntracked reports whether the file is not tracked by git
+func (f FileStatus) IsUntracked() bool {
+	return f.Index == '?'
+}
+
+// IsStaged reports whether the file has staged changes
+func (f FileStatus) IsStaged() bool {
+	return f.Index != ' ' && f.Index != '?'
+}
+
+// IsUnstaged reports whether the file has changes that are not staged
+func (f FileStatus) IsUnstaged() bool {
+	return f.Worktree != ' '
+}
+
+// Status lists the changed files with their diff stats against HEAD
+func Status() ([]FileStatus, error) {
+	return workingDir.Status()
+}
+
+// Status lists the changed files with their diff stats against HEAD
+func (r *ExecRepository) Status() ([]FileStatus, error) {
+	cmd := r.command("status", "--porcelain=v1", "-z", "--untracked-files=all")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git status: %w\n%s", err, stderr.String())
+	}
+	files := parseStatus(string(output))
+
+	// porcelain 输出的路径相对于仓库根目录
+	top, err := r.topLevel()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := r.diffStats()
+	for i := range files {
+		f := &files[i]
+		if f.IsUntracked() {
+			if info, err := os.Stat(filepath.Join(top, f.Path)); err == nil {
+				f.Size = info.Size()
+			}
+			continue
+		}
+		if s, ok := stats[f.Path]; ok {
+			f.Added, f.Deleted = s[0], s[1]
+		}
+	}
+
+	return files, nil
+}
+
+// parseStatus parses the output of git status --porcelain=v1 -z
+func parseStatus(output string) []FileStatus {
+	files := make([]FileStatus, 0)
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+
+		f := FileStatus{Index: entry[0], Worktree: entry[1], Path: entry[3:]}
+		// 重命名和复制的原路径是下一项
+		if (f.Index == 'R' || f.Index == 'C') && i+1 < len(entries) {
+			f.OrigPath = entries[i+1]
+			i++
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// diffStats returns the added and deleted lines per file between HEAD and the working tree
+func (r *ExecRepository) diffStats() map[string][2]int {
+	stats := make(map[string][2]int)
+
+	output, err := r.command("diff", "HEAD", "--numstat", "--no-renames", "-z").Output()
+	if err != nil {
+		return stats
+	}
+
+	for _, line := range strings.Split(string(output), "\x00") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		// 二进制文件显示为 "-"
+		added, err1 := strconv.Atoi(fields[0])
+		deleted, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			added, deleted = -1, -1
+		}
+		stats[fields[2]] = [2]int{added, deleted}
+	}
+	return stats
+}
+
+// StagedDiff returns the stat and patch of the index against base (HEAD when
+// empty), cut to maxBytes
+func StagedDiff(base string, maxBytes int) (string, error) {
+	args := []string{"diff", "--cached", "--stat", "--patch", "--no-color"}
+	if base != "" {
+		args = append(args, base)
+	}
+	cmd := exec.Command("git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get staged diff: %w", err)
+	}
+
+	diff := string(output)
+	if maxBytes > 0 && len(diff) > maxBytes {
+		diff = diff[:maxBytes] + "\n[diff truncated]"
+	}
+	return diff, nil
+}
+
+// BranchPatches returns the changes of every commit in base..HEAD, with no
+// context lines, as unified diffs. Unlike a diff of the branch it also has the
+// lines that a later commit removed again.
+func BranchPatches(base string) (string, error) {
+	// 不转义非 ASCII 路径，和 BranchFiles 的路径一致
+	cmd := exec.Command("git", "-c", "core.quotePath=false", "log", "-p", "-U0", "--format=", "--no-color", "--no-ext-diff", "--no-renames", base+"..HEAD")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the commits since %s: %w\n%s", base, err, stderr.String())
+	}
+	return string(output), nil
+}
+
+// BranchHistoryFiles returns the files added or modified by any commit in
+// base..HEAD, with their largest size in those commits, including files that a
+// later commit deleted again
+func BranchHistoryFiles(base string) (map[string]int64, error) {
+	cmd := exec.Command("git", "log", "--raw", "-z", "--no-abbrev", "--no-renames", "--diff-filter=AM", "--format=", base+"..HEAD")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the files changed since %s: %w\n%s", base, err, stderr.String())
+	}
+
+	// 每项为 ":<旧模式> <新模式> <旧 blob> <新 blob> <状态>\x00<路径>\x00"
+	files := make([]string, 0)
+	blobs := make([]string, 0)
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		meta := strings.Fields(strings.TrimSpace(fields[i]))
+		if len(meta) < 4 {
+			continue
+		}
+		files = append(files, fields[i+1])
+		blobs = append(blobs, meta[3])
+	}
+	sizes := make(map[string]int64)
+	if len(files) == 0 {
+		return sizes, nil
+	}
+
+	var input bytes.Buffer
+	for _, blob := range blobs {
+		fmt.Fprintln(&input, blob)
+	}
+	cmd = exec.Command("git", "cat-file", "--batch-check=%(objectsize)")
+	cmd.Stdin = &input
+	output, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file sizes: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	for i, file := range files {
+		var size int64
+		if i < len(lines) {
+			// 子模块的提交不在本仓库中，输出 "missing"，大小记为 0
+			size, _ = strconv.ParseInt(lines[i], 10, 64)
+		}
+		if current, ok := sizes[file]; !ok || size > current {
+			sizes[file] = size
+		}
+	}
+	return sizes, nil
+}
+
+// BranchFiles returns the files added or modified in HEAD since its merge
+// base with base, with their size in HEAD
+func BranchFiles(base string) (map[string]int64, error) {
+	cmd := exec.Command("git", "diff", "--name-only", "-z", "--no-renames", "--diff-filter=AM", base+"...HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files since %s: %w", base, err)
+	}
+
+	files := make([]string, 0)
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	sizes := make(map[string]int64, len(files))
+	if len(files) == 0 {
+		return sizes, nil
+	}
+
+	// 一次查询所有文件在 HEAD 中的大小，输出格式: <size>
+	var input bytes.Buffer
+	for _, file := range files {
+		fmt.Fprintf(&input, "HEAD:%s\n", file)
+	}
+	cmd = exec.Command("git", "cat-file", "--batch-check=%(objectsize)")
+	cmd.Stdin = &input
+	output, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file sizes: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	for i, file := range files {
+		if i < len(lines) {
+			// 子模块等不是 blob 的条目输出 "missing"，大小记为 0
+			sizes[file], _ = strconv.ParseInt(lines[i], 10, 64)
+		}
+	}
+	return sizes, nil
+}
+
+// HasStagedChanges reports whether the index differs from HEAD
+func HasStagedChanges() bool {
+	cmd := exec.Command("git", "diff", "--cached", "--quiet")
+	return cmd.Run() != nil
+}
+
+// TopLevel returns the root directory of the working tree
+func TopLevel() (string, error) {
+	return workingDir.topLevel()
+}
+
+// FilePathspec turns a path relative to the repository root (as reported by
+// Status) into a pathspec that matches exactly that file from any directory
+func FilePathspec(file string) string {
+	return ":(top,literal)" + file
+}
+
+// StagePaths stages the changes (including deletions) matching the given pathspecs
+func StagePaths(pathspecs []string) error {
+	args := append([]string{"add", "--all", "--"}, pathspecs...)
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to stage changes: %w\n%s", err, stderr.String())
+	}
+
+	return nil
+}
+
+// PathsToStage lists the files StagePaths would stage for the given pathspecs,
+// without staging anything
+func PathsToStage(pathspecs []string) ([]string, error) {
+	args := append([]string{"add", "--all", "--dry-run", "--"}, pathspecs...)
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("invalid paths: %w\n%s", err, stderr.String())
+	}
+
+	// 输出格式: add 'path' 或 remove 'path'
+	files := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		_, file, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		files = append(files, strings.Trim(file, "'"))
+	}
+	return files, nil
+}
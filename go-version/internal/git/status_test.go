@@ -0,0 +1,125 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/git/testrepo"
+)
+
+func TestParseStatus(t *testing.T) {
+	output := " M a.txt\x00MM b.txt\x00R  new.txt\x00old.txt\x00?? dir/c.txt\x00D  d.txt\x00"
+
+	want := []FileStatus{
+		{Path: "a.txt", Index: ' ', Worktree: 'M'},
+		{Path: "b.txt", Index: 'M', Worktree: 'M'},
+		{Path: "new.txt", OrigPath: "old.txt", Index: 'R', Worktree: ' '},
+		{Path: "dir/c.txt", Index: '?', Worktree: '?'},
+		{Path: "d.txt", Index: 'D', Worktree: ' '},
+	}
+
+	got := parseStatus(output)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseStatus() = %+v, want %+v", got, want)
+	}
+
+	if !got[0].IsUnstaged() || got[0].IsStaged() {
+		t.Errorf("a.txt should be unstaged only")
+	}
+	if !got[1].IsStaged() || !got[1].IsUnstaged() {
+		t.Errorf("b.txt should be staged and unstaged")
+	}
+	if !got[3].IsUntracked() || got[3].IsStaged() {
+		t.Errorf("dir/c.txt should be untracked")
+	}
+}
+
+func TestStatusDiffStats(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+
+	testrepo.WriteFile(t, ".", "a.txt", "one\ntwo\nthree\n")
+	testrepo.WriteFile(t, ".", "sub/new.txt", "new\n")
+
+	files, err := Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Status() returned %d files, want 2: %+v", len(files), files)
+	}
+
+	for _, f := range files {
+		switch f.Path {
+		case "a.txt":
+			if f.Added != 2 || f.Deleted != 0 {
+				t.Errorf("a.txt stats = +%d -%d, want +2 -0", f.Added, f.Deleted)
+			}
+		case "sub/new.txt":
+			if !f.IsUntracked() || f.Size != 4 {
+				t.Errorf("sub/new.txt = %+v, want untracked with size 4", f)
+			}
+		default:
+			t.Errorf("unexpected file %s", f.Path)
+		}
+	}
+
+	staged, err := PathsToStage([]string{FilePathspec("sub/new.txt")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(staged, []string{"sub/new.txt"}) {
+		t.Errorf("PathsToStage() = %v, want [sub/new.txt]", staged)
+	}
+}
+
+func TestBranchFiles(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+	testrepo.Run(t, ".", "checkout", "-q", "-b", "feature")
+	testrepo.WriteFile(t, ".", "dir/new file.txt", "12345")
+	testrepo.WriteFile(t, ".", "a.txt", "two\n")
+	testrepo.Run(t, ".", "add", "-A")
+	testrepo.Run(t, ".", "commit", "-q", "-m", "change")
+
+	files, err := BranchFiles("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int64{"a.txt": 4, "dir/new file.txt": 5}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("BranchFiles() = %v, want %v", files, want)
+	}
+}
+
+func TestBranchHistory(t *testing.T) {
+	testrepo.Chdir(t, testrepo.New(t))
+	testrepo.Run(t, ".", "checkout", "-q", "-b", "feature")
+	testrepo.WriteFile(t, ".", ".env", "TOKEN=secret\n")
+	testrepo.WriteFile(t, ".", "dir/new file.txt", "12345")
+	testrepo.Run(t, ".", "add", "-A")
+	testrepo.Run(t, ".", "commit", "-q", "-m", "add")
+	testrepo.Run(t, ".", "rm", "-q", ".env")
+	testrepo.WriteFile(t, ".", "dir/new file.txt", "1")
+	testrepo.Run(t, ".", "commit", "-q", "-am", "remove")
+
+	files, err := BranchHistoryFiles("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int64{".env": 13, "dir/new file.txt": 5}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("BranchHistoryFiles() = %v, want %v", files, want)
+	}
+
+	patches, err := BranchPatches("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(patches, "+++ b/.env") || !strings.Contains(patches, "\n+TOKEN=secret\n") {
+		t.Errorf("BranchPatches() = %q, missing the removed lines", patches)
+	}
+
+	if _, err := BranchHistoryFiles("missing"); err == nil {
+		t.Error("BranchHistoryFiles() with a missing base should fail")
+	}
+}
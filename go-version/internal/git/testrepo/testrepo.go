@@ -0,0 +1,76 @@
+// Package testrepo creates temporary on-disk git repositories for tests. It
+// doesn't depend on internal/git, so the tests of that package can use it.
+package testrepo
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// New creates a repository in a temporary directory, on main with one commit
+// of a.txt and a bare origin remote, and returns its path. It sets the commit
+// identity in the environment, so tests using it can't run in parallel.
+func New(t *testing.T) string {
+	t.Helper()
+
+	for _, kv := range [][2]string{
+		{"GIT_AUTHOR_NAME", "test"}, {"GIT_AUTHOR_EMAIL", "test@example.com"},
+		{"GIT_COMMITTER_NAME", "test"}, {"GIT_COMMITTER_EMAIL", "test@example.com"},
+	} {
+		t.Setenv(kv[0], kv[1])
+	}
+
+	root := t.TempDir()
+	dir, origin := filepath.Join(root, "repo"), filepath.Join(root, "origin.git")
+	Run(t, root, "init", "-q", "--bare", "-b", "main", origin)
+	Run(t, root, "init", "-q", "-b", "main", dir)
+	WriteFile(t, dir, "a.txt", "one\n")
+	Run(t, dir, "add", "a.txt")
+	Run(t, dir, "commit", "-q", "-m", "init")
+	Run(t, dir, "remote", "add", "origin", origin)
+	Run(t, dir, "push", "-q", "-u", "origin", "main")
+	return dir
+}
+
+// Chdir changes the working directory to dir until the end of the test, for
+// code that works on the repository of the working directory
+func Chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+}
+
+// Run runs git in dir and returns its output, failing the test on error
+func Run(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, output)
+	}
+	return string(output)
+}
+
+// WriteFile writes a file relative to dir, creating parent directories
+func WriteFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
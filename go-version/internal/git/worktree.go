@@ -0,0 +1,221 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Worktree is an entry of `git worktree list`
+type Worktree struct {
+	Path     string
+	HEAD     string
+	Branch   string // 分离 HEAD 时为空
+	Prunable bool   // 目录已不存在
+}
+
+// FetchPullRequest fetches the head of a pull request from the remote into FETCH_HEAD.
+// GitHub exposes every PR as refs/pull/<number>/head, including PRs from forks.
+func FetchPullRequest(remote string, number int) error {
+	ref := fmt.Sprintf("pull/%d/head", number)
+	cmd := exec.Command("git", "fetch", remote, ref)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to fetch %s from %s: %w\n%s", ref, remote, err, stderr.String())
+	}
+
+	return nil
+}
+
+// BranchExists checks if a local branch exists
+func BranchExists(branchName string) bool {
+	return workingDir.BranchExists(branchName)
+}
+
+// CreateBranchAt creates a branch at the given start point without checking it out
+func CreateBranchAt(branchName, startPoint string) error {
+	cmd := exec.Command("git", "branch", branchName, startPoint)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w\n%s", branchName, err, stderr.String())
+	}
+
+	return nil
+}
+
+// SetBranchUpstream configures the remote (name or URL) and remote branch a local branch tracks
+func SetBranchUpstream(branchName, remote, remoteBranch string) error {
+	settings := [][2]string{
+		{"branch." + branchName + ".remote", remote},
+		{"branch." + branchName + ".merge", "refs/heads/" + remoteBranch},
+	}
+
+	for _, kv := range settings {
+		cmd := exec.Command("git", "config", kv[0], kv[1])
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to set upstream of %s: %w\n%s", branchName, err, stderr.String())
+		}
+	}
+
+	return nil
+}
+
+// MergeFastForward fast-forwards the branch checked out in the worktree at dir to ref
+func MergeFastForward(dir, ref string) error {
+	cmd := exec.Command("git", "merge", "--ff-only", ref)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to fast-forward to %s: %w\n%s", ref, err, stderr.String())
+	}
+
+	return nil
+}
+
+// UpdateBranchRef points a branch that is not checked out at a new commit, fast-forward only
+func UpdateBranchRef(branchName, ref string) error {
+	sha, err := RevParse(ref)
+	if err != nil {
+		return err
+	}
+
+	// 用 fetch 到本地仓库实现仅快进的更新，不影响工作区
+	cmd := exec.Command("git", "fetch", ".", sha+":refs/heads/"+branchName)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to update branch %s: %w\n%s", branchName, err, stderr.String())
+	}
+
+	return nil
+}
+
+// RevParse resolves a revision to its commit SHA
+func RevParse(rev string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", rev+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", rev, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ListWorktrees lists the worktrees of the repository, the main worktree first
+func ListWorktrees() ([]Worktree, error) {
+	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	return parseWorktrees(string(output)), nil
+}
+
+func parseWorktrees(output string) []Worktree {
+	worktrees := make([]Worktree, 0)
+	var current *Worktree
+
+	for _, line := range strings.Split(output, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "worktree":
+			worktrees = append(worktrees, Worktree{Path: value})
+			current = &worktrees[len(worktrees)-1]
+		case "HEAD":
+			if current != nil {
+				current.HEAD = value
+			}
+		case "branch":
+			if current != nil {
+				current.Branch = strings.TrimPrefix(value, "refs/heads/")
+			}
+		case "prunable":
+			if current != nil {
+				current.Prunable = true
+			}
+		}
+	}
+
+	return worktrees
+}
+
+// AddWorktree checks out an existing branch in a new worktree at path
+func AddWorktree(path, branchName string) error {
+	cmd := exec.Command("git", "worktree", "add", path, branchName)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to add worktree %s: %w\n%s", path, err, stderr.String())
+	}
+
+	return nil
+}
+
+// RemoveWorktree removes a worktree; force discards its uncommitted changes
+func RemoveWorktree(path string, force bool) error {
+	args := []string{"worktree", "remove", path}
+	if force {
+		args = append(args, "--force")
+	}
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to remove worktree %s: %w\n%s", path, err, stderr.String())
+	}
+
+	return nil
+}
+
+// PruneWorktrees removes administrative data of worktrees whose directory is gone
+func PruneWorktrees() error {
+	cmd := exec.Command("git", "worktree", "prune")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %w\n%s", err, stderr.String())
+	}
+
+	return nil
+}
+
+// HasUncommittedChangesIn checks if the working tree at path has uncommitted changes
+func HasUncommittedChangesIn(path string) (bool, error) {
+	cmd := exec.Command("git", "-C", path, "status", "--porcelain")
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to check git status of %s: %w", path, err)
+	}
+
+	return len(output) > 0, nil
+}
+
+// GetConfig reads a git config value, returning "" when it is not set
+func GetConfig(key string) string {
+	cmd := exec.Command("git", "config", "--get", key)
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
+// GitPath resolves a path inside the git directory of the current worktree
+func GitPath(name string) (string, error) {
+	return workingDir.gitPath(name)
+}
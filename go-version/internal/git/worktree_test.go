@@ -0,0 +1,67 @@
+package git
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Wangggym/quick-workflow/internal/git/testrepo"
+)
+
+func TestParseWorktrees(t *testing.T) {
+	output := `worktree /src/app
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /src/app.worktrees/pr-12
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/feature/login
+
+worktree /src/app.worktrees/pr-7
+HEAD 3333333333333333333333333333333333333333
+detached
+prunable gitdir file points to non-existent location
+
+`
+
+	want := []Worktree{
+		{Path: "/src/app", HEAD: "1111111111111111111111111111111111111111", Branch: "main"},
+		{Path: "/src/app.worktrees/pr-12", HEAD: "2222222222222222222222222222222222222222", Branch: "feature/login"},
+		{Path: "/src/app.worktrees/pr-7", HEAD: "3333333333333333333333333333333333333333", Prunable: true},
+	}
+
+	got := parseWorktrees(output)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWorktrees() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMergeFastForwardInWorktree(t *testing.T) {
+	dir := testrepo.New(t)
+	testrepo.Chdir(t, dir)
+	testrepo.Run(t, ".", "branch", "feature")
+	path := filepath.Join(t.TempDir(), "feature")
+	testrepo.Run(t, ".", "worktree", "add", "-q", path, "feature")
+
+	testrepo.Run(t, ".", "checkout", "-q", "-b", "next")
+	testrepo.WriteFile(t, ".", "b.txt", "two\n")
+	testrepo.Run(t, ".", "add", "b.txt")
+	testrepo.Run(t, ".", "commit", "-q", "-m", "next")
+	testrepo.Run(t, ".", "checkout", "-q", "main")
+
+	// FETCH_HEAD 写在主 worktree 中，在链接的 worktree 中无法解析
+	testrepo.Run(t, ".", "fetch", "-q", dir, "next")
+	sha, err := RevParse("FETCH_HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := MergeFastForward(path, sha); err != nil {
+		t.Fatal(err)
+	}
+
+	head := strings.TrimSpace(testrepo.Run(t, ".", "-C", path, "rev-parse", "HEAD"))
+	if head != sha {
+		t.Errorf("worktree HEAD = %s, want %s", head, sha)
+	}
+}
@@ -47,6 +47,94 @@ type PullRequest struct {
 	State    string
 	MergedAt string
 	MergedBy string
+	Merged   bool
+	Author   string
+	Labels   []string
+
+	// 源分支所在仓库，来自 fork 的 PR 与 base 仓库不同
+	HeadOwner    string
+	HeadRepo     string
+	HeadCloneURL string
+	HeadSHA      string
+
+	// 合并后目标分支上的提交（merge commit、squash 或 rebase 的最后一个提交）
+	MergeCommitSHA string
+	// PR 的提交数，列表接口不返回，为 0
+	Commits int
+}
+
+// IsCrossRepository reports whether the PR head lives in a different repository (a fork)
+func (pr *PullRequest) IsCrossRepository(owner, repo string) bool {
+	if pr.HeadOwner == "" {
+		return false
+	}
+	return !strings.EqualFold(pr.HeadOwner, owner) || !strings.EqualFold(pr.HeadRepo, repo)
+}
+
+// HeadRepository returns the repository holding the PR head, defaulting to the base repository
+func (pr *PullRequest) HeadRepository(owner, repo string) (string, string) {
+	if pr.HeadOwner == "" {
+		return owner, repo
+	}
+	return pr.HeadOwner, pr.HeadRepo
+}
+
+// HeadLabel returns the head branch, prefixed with its owner when it comes from a fork
+func (pr *PullRequest) HeadLabel(owner, repo string) string {
+	if pr.IsCrossRepository(owner, repo) {
+		return pr.HeadOwner + ":" + pr.Head
+	}
+	return pr.Head
+}
+
+// headFilter builds the "owner:branch" head filter of the PR list API;
+// branch may already be qualified with the owner of a fork
+func headFilter(owner, branch string) string {
+	if strings.Contains(branch, ":") {
+		return branch
+	}
+	return fmt.Sprintf("%s:%s", owner, branch)
+}
+
+// newPullRequest converts a go-github pull request into our PullRequest
+func newPullRequest(pr *github.PullRequest) *PullRequest {
+	mergedAt := ""
+	if pr.MergedAt != nil {
+		mergedAt = pr.MergedAt.Format("2006-01-02T15:04:05Z")
+	}
+
+	mergedBy := ""
+	if pr.MergedBy != nil {
+		mergedBy = pr.MergedBy.GetLogin()
+	}
+
+	labels := make([]string, 0, len(pr.Labels))
+	for _, label := range pr.Labels {
+		labels = append(labels, label.GetName())
+	}
+
+	return &PullRequest{
+		Number:   pr.GetNumber(),
+		Title:    pr.GetTitle(),
+		Body:     pr.GetBody(),
+		HTMLURL:  pr.GetHTMLURL(),
+		Head:     pr.GetHead().GetRef(),
+		Base:     pr.GetBase().GetRef(),
+		State:    pr.GetState(),
+		MergedAt: mergedAt,
+		MergedBy: mergedBy,
+		Merged:   pr.GetMerged() || pr.MergedAt != nil,
+		Author:   pr.GetUser().GetLogin(),
+		Labels:   labels,
+
+		HeadOwner:    pr.GetHead().GetRepo().GetOwner().GetLogin(),
+		HeadRepo:     pr.GetHead().GetRepo().GetName(),
+		HeadCloneURL: pr.GetHead().GetRepo().GetCloneURL(),
+		HeadSHA:      pr.GetHead().GetSHA(),
+
+		MergeCommitSHA: pr.GetMergeCommitSHA(),
+		Commits:        pr.GetCommits(),
+	}
 }
 
 // CreatePullRequestInput contains the input for creating a PR
@@ -73,15 +161,7 @@ func (c *Client) CreatePullRequest(input CreatePullRequestInput) (*PullRequest,
 		return nil, fmt.Errorf("failed to create pull request: %w", err)
 	}
 
-	return &PullRequest{
-		Number:  pr.GetNumber(),
-		Title:   pr.GetTitle(),
-		Body:    pr.GetBody(),
-		HTMLURL: pr.GetHTMLURL(),
-		Head:    pr.GetHead().GetRef(),
-		Base:    pr.GetBase().GetRef(),
-		State:   pr.GetState(),
-	}, nil
+	return newPullRequest(pr), nil
 }
 
 // ListPullRequests lists pull requests with the given state and optional author filter
@@ -105,27 +185,36 @@ func (c *Client) ListPullRequests(owner, repo, state, author string) ([]PullRequ
 			continue
 		}
 
-		mergedAt := ""
-		if pr.MergedAt != nil {
-			mergedAt = pr.MergedAt.Format("2006-01-02T15:04:05Z")
+		result = append(result, *newPullRequest(pr))
+	}
+
+	return result, nil
+}
+
+// ListAllPullRequests lists every pull request with the given state, following pagination
+func (c *Client) ListAllPullRequests(owner, repo, state string) ([]PullRequest, error) {
+	opts := &github.PullRequestListOptions{
+		State: state,
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	result := make([]PullRequest, 0)
+	for {
+		prs, resp, err := c.client.PullRequests.List(c.ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", err)
 		}
 
-		mergedBy := ""
-		if pr.MergedBy != nil {
-			mergedBy = pr.MergedBy.GetLogin()
+		for _, pr := range prs {
+			result = append(result, *newPullRequest(pr))
 		}
 
-		result = append(result, PullRequest{
-			Number:   pr.GetNumber(),
-			Title:    pr.GetTitle(),
-			Body:     pr.GetBody(),
-			HTMLURL:  pr.GetHTMLURL(),
-			Head:     pr.GetHead().GetRef(),
-			Base:     pr.GetBase().GetRef(),
-			State:    pr.GetState(),
-			MergedAt: mergedAt,
-			MergedBy: mergedBy,
-		})
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
 	}
 
 	return result, nil
@@ -138,15 +227,64 @@ func (c *Client) GetPullRequest(owner, repo string, number int) (*PullRequest, e
 		return nil, fmt.Errorf("failed to get pull request: %w", err)
 	}
 
-	return &PullRequest{
-		Number:  pr.GetNumber(),
-		Title:   pr.GetTitle(),
-		Body:    pr.GetBody(),
-		HTMLURL: pr.GetHTMLURL(),
-		Head:    pr.GetHead().GetRef(),
-		Base:    pr.GetBase().GetRef(),
-		State:   pr.GetState(),
-	}, nil
+	return newPullRequest(pr), nil
+}
+
+// UpdatePullRequestInput contains the fields to change on a PR; nil fields are left unchanged
+type UpdatePullRequestInput struct {
+	Title *string
+	Body  *string
+	Base  *string
+	State *string // "open" or "closed"
+}
+
+// UpdatePullRequest edits a pull request
+func (c *Client) UpdatePullRequest(owner, repo string, number int, input UpdatePullRequestInput) (*PullRequest, error) {
+	update := &github.PullRequest{
+		Title: input.Title,
+		Body:  input.Body,
+		State: input.State,
+	}
+	if input.Base != nil {
+		update.Base = &github.PullRequestBranch{Ref: input.Base}
+	}
+
+	pr, _, err := c.client.PullRequests.Edit(c.ctx, owner, repo, number, update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update pull request #%d: %w", number, err)
+	}
+
+	return newPullRequest(pr), nil
+}
+
+// ClosePullRequest closes a pull request without merging
+func (c *Client) ClosePullRequest(owner, repo string, number int) (*PullRequest, error) {
+	return c.UpdatePullRequest(owner, repo, number, UpdatePullRequestInput{State: github.String("closed")})
+}
+
+// ReopenPullRequest reopens a closed pull request
+func (c *Client) ReopenPullRequest(owner, repo string, number int) (*PullRequest, error) {
+	return c.UpdatePullRequest(owner, repo, number, UpdatePullRequestInput{State: github.String("open")})
+}
+
+// AddLabels adds labels to a pull request
+func (c *Client) AddLabels(owner, repo string, number int, labels []string) error {
+	_, _, err := c.client.Issues.AddLabelsToIssue(c.ctx, owner, repo, number, labels)
+	if err != nil {
+		return fmt.Errorf("failed to add labels to PR #%d: %w", number, err)
+	}
+
+	return nil
+}
+
+// RemoveLabel removes a label from a pull request
+func (c *Client) RemoveLabel(owner, repo string, number int, label string) error {
+	_, err := c.client.Issues.RemoveLabelForIssue(c.ctx, owner, repo, number, label)
+	if err != nil {
+		return fmt.Errorf("failed to remove label %s from PR #%d: %w", label, number, err)
+	}
+
+	return nil
 }
 
 // MergePullRequest merges a pull request using squash merge by default
@@ -166,12 +304,13 @@ func (c *Client) MergePullRequest(owner, repo string, number int, commitMessage
 
 // GetCurrentRepository gets the owner and repo from git remote
 func GetCurrentRepository() (owner, repo string, err error) {
-	// 这里可以通过执行 git remote get-url origin 来获取
-	// 简化实现：从环境变量或配置读取
-	// 实际应该解析 git remote 输出
+	// fork 模式下 PR 位于 upstream 仓库
+	rc, err := DetectRepoContext()
+	if err != nil {
+		return "", "", err
+	}
 
-	// TODO: 实现通过 git 命令获取
-	return "", "", fmt.Errorf("not implemented: should parse git remote")
+	return rc.BaseOwner, rc.BaseRepo, nil
 }
 
 // ParseRepositoryFromURL parses owner and repo from GitHub URL
@@ -259,10 +398,10 @@ func IsPRURL(s string) bool {
 	return strings.HasPrefix(s, "github.com/") && strings.Contains(s, "/pull/")
 }
 
-// GetPRByBranch gets a pull request by branch name
+// GetPRByBranch gets a pull request by branch name ("branch" or "forkowner:branch")
 func (c *Client) GetPRByBranch(owner, repo, branch string) (*PullRequest, error) {
-	// 构建查询条件：head 应该是 owner:branch
-	head := fmt.Sprintf("%s:%s", owner, branch)
+	// 构建查询条件：head 应该是 owner:branch，fork 的分支为 forkowner:branch
+	head := headFilter(owner, branch)
 
 	opts := &github.PullRequestListOptions{
 		State: "open",
@@ -282,16 +421,163 @@ func (c *Client) GetPRByBranch(owner, repo, branch string) (*PullRequest, error)
 	}
 
 	// 返回第一个匹配的 PR
-	pr := prs[0]
-	return &PullRequest{
-		Number:  pr.GetNumber(),
-		Title:   pr.GetTitle(),
-		Body:    pr.GetBody(),
-		HTMLURL: pr.GetHTMLURL(),
-		Head:    pr.GetHead().GetRef(),
-		Base:    pr.GetBase().GetRef(),
-		State:   pr.GetState(),
-	}, nil
+	return newPullRequest(prs[0]), nil
+}
+
+// FindPRsByBranch finds pull requests whose head is the given branch ("branch" or "forkowner:branch")
+func (c *Client) FindPRsByBranch(owner, repo, branch, state string) ([]PullRequest, error) {
+	opts := &github.PullRequestListOptions{
+		State: state,
+		Head:  headFilter(owner, branch),
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	prs, _, err := c.client.PullRequests.List(c.ctx, owner, repo, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	result := make([]PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		result = append(result, *newPullRequest(pr))
+	}
+
+	return result, nil
+}
+
+// SearchPRsByKey searches pull requests in the repository that mention key in
+// their title or body, or whose head branch starts with it, in one GraphQL
+// request. state is "open", "closed" or "all".
+func (c *Client) SearchPRsByKey(owner, repo, key, state string) ([]PullRequest, error) {
+	query := `query($text: String!, $head: String!) {
+  text: search(query: $text, type: ISSUE, first: 100) { nodes { ...pr } }
+  head: search(query: $head, type: ISSUE, first: 100) { nodes { ...pr } }
+}
+fragment pr on PullRequest {
+  number
+  title
+  body
+  url
+  headRefName
+  baseRefName
+  headRefOid
+  state
+  mergedAt
+  author { login }
+  mergedBy { login }
+  labels(first: 20) { nodes { name } }
+  headRepository { name owner { login } url }
+  mergeCommit { oid }
+}`
+
+	type searchResult struct {
+		Nodes []searchPR `json:"nodes"`
+	}
+	var data struct {
+		Text searchResult `json:"text"`
+		Head searchResult `json:"head"`
+	}
+
+	qualifiers := fmt.Sprintf("repo:%s/%s is:pr", owner, repo)
+	if state == "open" || state == "closed" {
+		qualifiers += " is:" + state
+	}
+	variables := map[string]interface{}{
+		"text": fmt.Sprintf("%s %q", qualifiers, key),
+		"head": fmt.Sprintf("%s head:%s", qualifiers, key),
+	}
+	if err := c.graphQL(query, variables, &data); err != nil {
+		return nil, fmt.Errorf("failed to search pull requests: %w", err)
+	}
+
+	seen := make(map[int]bool)
+	result := make([]PullRequest, 0)
+	for _, node := range append(data.Text.Nodes, data.Head.Nodes...) {
+		// 搜索结果里的 issue 没有 PR 字段
+		if node.Number == 0 || seen[node.Number] {
+			continue
+		}
+		seen[node.Number] = true
+		result = append(result, node.pullRequest())
+	}
+
+	return result, nil
+}
+
+// searchPR is a pull request as returned by the GraphQL search
+type searchPR struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	Body        string `json:"body"`
+	URL         string `json:"url"`
+	HeadRefName string `json:"headRefName"`
+	BaseRefName string `json:"baseRefName"`
+	HeadRefOid  string `json:"headRefOid"`
+	State       string `json:"state"` // OPEN、CLOSED 或 MERGED
+	MergedAt    string `json:"mergedAt"`
+	Author      struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	MergedBy struct {
+		Login string `json:"login"`
+	} `json:"mergedBy"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	HeadRepository struct {
+		Name  string `json:"name"`
+		URL   string `json:"url"`
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"headRepository"`
+	MergeCommit struct {
+		Oid string `json:"oid"`
+	} `json:"mergeCommit"`
+}
+
+// pullRequest converts a search result into our PullRequest, with the REST state names
+func (pr searchPR) pullRequest() PullRequest {
+	state := "closed"
+	if pr.State == "OPEN" {
+		state = "open"
+	}
+
+	labels := make([]string, 0, len(pr.Labels.Nodes))
+	for _, label := range pr.Labels.Nodes {
+		labels = append(labels, label.Name)
+	}
+
+	cloneURL := ""
+	if pr.HeadRepository.URL != "" {
+		cloneURL = pr.HeadRepository.URL + ".git"
+	}
+
+	return PullRequest{
+		Number:   pr.Number,
+		Title:    pr.Title,
+		Body:     pr.Body,
+		HTMLURL:  pr.URL,
+		Head:     pr.HeadRefName,
+		Base:     pr.BaseRefName,
+		State:    state,
+		MergedAt: pr.MergedAt,
+		MergedBy: pr.MergedBy.Login,
+		Merged:   pr.State == "MERGED",
+		Author:   pr.Author.Login,
+		Labels:   labels,
+
+		HeadOwner:    pr.HeadRepository.Owner.Login,
+		HeadRepo:     pr.HeadRepository.Name,
+		HeadCloneURL: cloneURL,
+		HeadSHA:      pr.HeadRefOid,
+
+		MergeCommitSHA: pr.MergeCommit.Oid,
+	}
 }
 
 // AddPRComment adds a comment to a pull request
@@ -310,18 +596,55 @@ func (c *Client) AddPRComment(owner, repo string, prNumber int, body string) err
 
 // ApprovePullRequest approves a pull request
 func (c *Client) ApprovePullRequest(owner, repo string, number int, body string) error {
-	event := "APPROVE"
-	review := &github.PullRequestReviewRequest{
-		Event: &event,
+	return c.CreateReview(owner, repo, number, CreateReviewInput{
+		Event: ReviewEventApprove,
+		Body:  body,
+	})
+}
+
+// FilterPullRequests lists open pull requests by author and labels (all labels must match).
+// An author like "dependabot" also matches the bot account "dependabot[bot]".
+func (c *Client) FilterPullRequests(owner, repo, author string, labels []string) ([]PullRequest, error) {
+	prs, err := c.ListAllPullRequests(owner, repo, "open")
+	if err != nil {
+		return nil, err
 	}
 
-	if body != "" {
-		review.Body = &body
+	result := make([]PullRequest, 0)
+	for _, pr := range prs {
+		if author != "" && !strings.EqualFold(pr.Author, author) && !strings.EqualFold(pr.Author, author+"[bot]") {
+			continue
+		}
+		if !hasAllLabels(pr.Labels, labels) {
+			continue
+		}
+		result = append(result, pr)
 	}
 
-	_, _, err := c.client.PullRequests.CreateReview(c.ctx, owner, repo, number, review)
+	return result, nil
+}
+
+func hasAllLabels(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.EqualFold(h, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// DeleteBranch deletes a branch of the repository through the API
+func (c *Client) DeleteBranch(owner, repo, branch string) error {
+	_, err := c.client.Git.DeleteRef(c.ctx, owner, repo, "heads/"+branch)
 	if err != nil {
-		return fmt.Errorf("failed to approve pull request: %w", err)
+		return fmt.Errorf("failed to delete branch %s: %w", branch, err)
 	}
 
 	return nil
@@ -346,3 +669,29 @@ func (c *Client) IsPRMergeable(owner, repo string, number int) (bool, error) {
 
 	return true, nil
 }
+
+// RequiresSignedCommits reports whether branch protection or a repository
+// ruleset requires signed commits on the branch
+func (c *Client) RequiresSignedCommits(owner, repo, branch string) (bool, error) {
+	// 规则集对所有有读权限的用户可见，分支不存在时也按名称匹配
+	rules, _, err := c.client.Repositories.GetRulesForBranch(c.ctx, owner, repo, branch)
+	if err != nil {
+		return false, fmt.Errorf("failed to get rules of branch %s: %w", branch, err)
+	}
+	for _, rule := range rules {
+		if rule.Type == "required_signatures" {
+			return true, nil
+		}
+	}
+
+	// 传统的分支保护，未保护或没有管理员权限时返回 404
+	sig, resp, err := c.client.Repositories.GetSignaturesProtectedBranch(c.ctx, owner, repo, branch)
+	if err != nil {
+		if resp != nil && (resp.StatusCode == 404 || resp.StatusCode == 403) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get protection of branch %s: %w", branch, err)
+	}
+
+	return sig.GetEnabled(), nil
+}
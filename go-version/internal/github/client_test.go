@@ -1,6 +1,7 @@
 package github
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -258,3 +259,29 @@ func TestParseRepositoryFromURL(t *testing.T) {
 	}
 }
 
+
+func TestSearchPRPullRequest(t *testing.T) {
+	var node searchPR
+	data := `{"number": 7, "title": "PROJ-12: Fix login", "headRefName": "PROJ-12--fix-login",
+		"baseRefName": "main", "state": "MERGED", "mergeCommit": {"oid": "abc"},
+		"headRepository": {"name": "repo", "url": "https://github.com/alice/repo", "owner": {"login": "alice"}}}`
+	if err := json.Unmarshal([]byte(data), &node); err != nil {
+		t.Fatal(err)
+	}
+
+	pr := node.pullRequest()
+	if pr.Number != 7 || pr.Head != "PROJ-12--fix-login" || pr.Base != "main" {
+		t.Errorf("pullRequest() = %+v", pr)
+	}
+	if pr.State != "closed" || !pr.Merged || pr.MergeCommitSHA != "abc" {
+		t.Errorf("pullRequest() state = %q, merged = %v, merge commit = %q, want closed, true, abc", pr.State, pr.Merged, pr.MergeCommitSHA)
+	}
+	if pr.HeadOwner != "alice" || pr.HeadCloneURL != "https://github.com/alice/repo.git" {
+		t.Errorf("pullRequest() head repo = %s, %s", pr.HeadOwner, pr.HeadCloneURL)
+	}
+
+	node.State = "OPEN"
+	if pr := node.pullRequest(); pr.State != "open" || pr.Merged {
+		t.Errorf("open pullRequest() state = %q, merged = %v", pr.State, pr.Merged)
+	}
+}
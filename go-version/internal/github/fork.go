@@ -0,0 +1,162 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+	"github.com/google/go-github/v57/github"
+)
+
+// Remote names used for the fork workflow
+const (
+	// UpstreamRemote points at the original repository when origin is a fork
+	UpstreamRemote = "upstream"
+	// ForkRemote points at the user's fork when origin is the original repository
+	ForkRemote = "fork"
+)
+
+// RepoContext describes where PRs are opened and where branches are pushed.
+// Without a fork both sides are the origin repository.
+type RepoContext struct {
+	BaseOwner  string
+	BaseRepo   string
+	BaseRemote string // 拉取目标分支的 remote
+	HeadOwner  string
+	HeadRepo   string
+	HeadRemote string // 推送分支的 remote
+}
+
+// IsFork reports whether branches are pushed to a different repository than PRs target
+func (rc *RepoContext) IsFork() bool {
+	return !strings.EqualFold(rc.BaseOwner, rc.HeadOwner) || !strings.EqualFold(rc.BaseRepo, rc.HeadRepo)
+}
+
+// HeadRef returns the PR head for a branch: "branch", or "owner:branch" for forks
+func (rc *RepoContext) HeadRef(branch string) string {
+	if rc.IsFork() {
+		return rc.HeadOwner + ":" + branch
+	}
+	return branch
+}
+
+// DetectRepoContext determines the base and head repositories from the git remotes:
+//   - origin + upstream: origin is the fork, PRs target upstream
+//   - origin + fork:     origin is the original repository, branches go to fork
+//   - origin only:       no fork
+func DetectRepoContext() (*RepoContext, error) {
+	if !git.IsGitRepository() {
+		return nil, fmt.Errorf("not a git repository")
+	}
+
+	originOwner, originRepo, err := RemoteRepository("origin")
+	if err != nil {
+		return nil, err
+	}
+
+	rc := &RepoContext{
+		BaseOwner:  originOwner,
+		BaseRepo:   originRepo,
+		BaseRemote: "origin",
+		HeadOwner:  originOwner,
+		HeadRepo:   originRepo,
+		HeadRemote: "origin",
+	}
+
+	if git.RemoteExists(UpstreamRemote) {
+		owner, repo, err := RemoteRepository(UpstreamRemote)
+		if err != nil {
+			return nil, err
+		}
+		rc.BaseOwner, rc.BaseRepo, rc.BaseRemote = owner, repo, UpstreamRemote
+		return rc, nil
+	}
+
+	if git.RemoteExists(ForkRemote) {
+		owner, repo, err := RemoteRepository(ForkRemote)
+		if err != nil {
+			return nil, err
+		}
+		rc.HeadOwner, rc.HeadRepo, rc.HeadRemote = owner, repo, ForkRemote
+	}
+
+	return rc, nil
+}
+
+// RemoteRepository returns the owner and name of the GitHub repository a remote points to
+func RemoteRepository(remote string) (string, string, error) {
+	url, err := git.GetRemoteURLFor(remote)
+	if err != nil {
+		return "", "", err
+	}
+	return ParseRepositoryFromURL(url)
+}
+
+// Repository is a GitHub repository
+type Repository struct {
+	Owner    string
+	Name     string
+	CloneURL string
+	SSHURL   string
+}
+
+// GetAuthenticatedUser returns the login of the token owner
+func (c *Client) GetAuthenticatedUser() (string, error) {
+	user, _, err := c.client.Users.Get(c.ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+
+	return user.GetLogin(), nil
+}
+
+// CanPush reports whether the authenticated user can push to the repository
+func (c *Client) CanPush(owner, repo string) (bool, error) {
+	repository, _, err := c.client.Repositories.Get(c.ctx, owner, repo)
+	if err != nil {
+		return false, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	return repository.GetPermissions()["push"], nil
+}
+
+// CreateFork forks the repository into the authenticated user's account and
+// waits until it is ready. An existing fork is returned as is.
+func (c *Client) CreateFork(owner, repo string) (*Repository, error) {
+	fork, _, err := c.client.Repositories.CreateFork(c.ctx, owner, repo, &github.RepositoryCreateForkOptions{})
+	if err != nil {
+		// GitHub 异步创建 fork，返回 202
+		var accepted *github.AcceptedError
+		if !errors.As(err, &accepted) {
+			return nil, fmt.Errorf("failed to create fork: %w", err)
+		}
+	}
+
+	login, err := c.GetAuthenticatedUser()
+	if err != nil {
+		return nil, err
+	}
+
+	name := repo
+	if fork != nil && fork.GetName() != "" {
+		name = fork.GetName()
+	}
+
+	// 等待 fork 可用
+	for i := 0; i < 15; i++ {
+		repository, _, err := c.client.Repositories.Get(c.ctx, login, name)
+		if err == nil {
+			return &Repository{
+				Owner:    repository.GetOwner().GetLogin(),
+				Name:     repository.GetName(),
+				CloneURL: repository.GetCloneURL(),
+				SSHURL:   repository.GetSSHURL(),
+			}, nil
+		}
+		time.Sleep(2 * time.Second)
+	}
+
+	return nil, fmt.Errorf("fork %s/%s was not ready in time, try again later", login, name)
+}
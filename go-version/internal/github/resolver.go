@@ -0,0 +1,275 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/git"
+)
+
+// PRRefKind describes how a PR was referenced on the command line
+type PRRefKind int
+
+const (
+	// PRRefCurrentBranch means no reference was given, the current branch is used
+	PRRefCurrentBranch PRRefKind = iota
+	// PRRefURL is a full GitHub PR URL
+	PRRefURL
+	// PRRefNumber is a PR number in the current repository (123 or #123)
+	PRRefNumber
+	// PRRefRepoNumber is a PR number in another repository (owner/repo#123)
+	PRRefRepoNumber
+	// PRRefJiraKey is a Jira issue key contained in the PR branch or title
+	PRRefJiraKey
+	// PRRefBranch is the head branch name of the PR
+	PRRefBranch
+)
+
+var (
+	// ErrPRNotFound is returned when no pull request matches the reference
+	ErrPRNotFound = errors.New("no matching pull request found")
+	// ErrPRSelectionCancelled is returned when the user declines to pick a PR interactively
+	ErrPRSelectionCancelled = errors.New("pull request selection cancelled")
+
+	repoNumberPattern = regexp.MustCompile(`^([\w.-]+)/([\w.-]+)#(\d+)$`)
+	jiraKeyPattern    = regexp.MustCompile(`^[A-Z][A-Z0-9]+-\d+$`)
+	// jiraKeyTokenPattern finds Jira keys that are whole tokens, in any case
+	jiraKeyTokenPattern = regexp.MustCompile(`(?i)(?:^|[^A-Z0-9])([A-Z][A-Z0-9]+-\d+)`)
+)
+
+// PRRef is a parsed PR reference
+type PRRef struct {
+	Kind    PRRefKind
+	Raw     string
+	Owner   string
+	Repo    string
+	Number  int
+	JiraKey string
+	Branch  string
+}
+
+// ResolveOptions controls how a PR reference is resolved
+type ResolveOptions struct {
+	// IncludeClosed also matches closed and merged PRs for branch and Jira key lookups
+	IncludeClosed bool
+	// Select is called with a reason and the candidates when the reference is ambiguous
+	// or cannot be resolved. It returns nil when the user declines. A nil Select disables
+	// the interactive fallback.
+	Select func(reason string, candidates []PullRequest) (*PullRequest, error)
+}
+
+// ResolvedPR is a pull request together with the repository it belongs to
+type ResolvedPR struct {
+	Owner string
+	Repo  string
+	PR    *PullRequest
+	Ref   *PRRef
+}
+
+// ParsePRRef parses a PR reference. Supported formats:
+// - "" (use the current branch)
+// - https://github.com/owner/repo/pull/123
+// - 123 or #123
+// - owner/repo#123
+// - PROJ-123 (Jira key)
+// - any other string is treated as a branch name
+func ParsePRRef(arg string) (*PRRef, error) {
+	arg = strings.TrimSpace(arg)
+	ref := &PRRef{Raw: arg}
+
+	switch {
+	case arg == "":
+		ref.Kind = PRRefCurrentBranch
+
+	case IsPRURL(arg):
+		owner, repo, number, err := ParsePRFromURL(arg)
+		if err != nil {
+			return nil, err
+		}
+		ref.Kind = PRRefURL
+		ref.Owner, ref.Repo, ref.Number = owner, repo, number
+
+	case repoNumberPattern.MatchString(arg):
+		m := repoNumberPattern.FindStringSubmatch(arg)
+		number, _ := strconv.Atoi(m[3])
+		ref.Kind = PRRefRepoNumber
+		ref.Owner, ref.Repo, ref.Number = m[1], m[2], number
+
+	case jiraKeyPattern.MatchString(arg):
+		ref.Kind = PRRefJiraKey
+		ref.JiraKey = arg
+
+	default:
+		number, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+		if err == nil {
+			if number <= 0 {
+				return nil, fmt.Errorf("invalid PR number: %s", arg)
+			}
+			ref.Kind = PRRefNumber
+			ref.Number = number
+			break
+		}
+		if strings.HasPrefix(arg, "#") || strings.ContainsAny(arg, " \t~^:?*[\\") {
+			return nil, fmt.Errorf("invalid PR reference: %s (expected number, URL, owner/repo#number, Jira key or branch)", arg)
+		}
+		ref.Kind = PRRefBranch
+		ref.Branch = arg
+	}
+
+	return ref, nil
+}
+
+// ResolvePR resolves a PR reference to a pull request
+func (c *Client) ResolvePR(arg string, opts ResolveOptions) (*ResolvedPR, error) {
+	ref, err := ParsePRRef(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, repo := ref.Owner, ref.Repo
+	if owner == "" {
+		owner, repo, err = GetCurrentRepository()
+		if err != nil {
+			return nil, fmt.Errorf("cannot determine repository (%v); use the full PR URL or owner/repo#number", err)
+		}
+	}
+
+	resolved := &ResolvedPR{Owner: owner, Repo: repo, Ref: ref}
+
+	switch ref.Kind {
+	case PRRefURL, PRRefNumber, PRRefRepoNumber:
+		pr, err := c.GetPullRequest(owner, repo, ref.Number)
+		if err != nil {
+			return nil, fmt.Errorf("PR #%d in %s/%s: %w", ref.Number, owner, repo, err)
+		}
+		resolved.PR = pr
+		return resolved, nil
+
+	case PRRefCurrentBranch, PRRefBranch:
+		branch := ref.Branch
+		if branch == "" {
+			branch, err = git.GetCurrentBranch()
+			if err != nil || branch == "" {
+				return c.selectPR(resolved, opts, "Could not determine the current branch", nil)
+			}
+			// fork 模式下当前分支推送在 fork 仓库
+			if ref.Owner == "" {
+				if rc, err := DetectRepoContext(); err == nil {
+					branch = rc.HeadRef(branch)
+				}
+			}
+		}
+		candidates, err := c.findPRsForBranch(owner, repo, branch, opts.IncludeClosed)
+		if err != nil {
+			return nil, err
+		}
+		return c.pick(resolved, opts, fmt.Sprintf("branch %s", branch), candidates)
+
+	case PRRefJiraKey:
+		candidates, err := c.findPRsForJiraKey(owner, repo, ref.JiraKey, opts.IncludeClosed)
+		if err != nil {
+			return nil, err
+		}
+		return c.pick(resolved, opts, fmt.Sprintf("Jira ticket %s", ref.JiraKey), candidates)
+	}
+
+	return nil, fmt.Errorf("unsupported PR reference: %s", arg)
+}
+
+// findPRsForBranch returns open PRs for the branch, falling back to closed ones if allowed
+func (c *Client) findPRsForBranch(owner, repo, branch string, includeClosed bool) ([]PullRequest, error) {
+	prs, err := c.FindPRsByBranch(owner, repo, branch, "open")
+	if err != nil {
+		return nil, err
+	}
+	if len(prs) > 0 || !includeClosed {
+		return prs, nil
+	}
+
+	return c.FindPRsByBranch(owner, repo, branch, "all")
+}
+
+// findPRsForJiraKey returns PRs whose head branch or title contains the Jira key
+func (c *Client) findPRsForJiraKey(owner, repo, key string, includeClosed bool) ([]PullRequest, error) {
+	state := "open"
+	if includeClosed {
+		state = "all"
+	}
+
+	found, err := c.SearchPRsByKey(owner, repo, key, state)
+	if err != nil {
+		return nil, err
+	}
+
+	// 搜索也会匹配正文，只保留分支名或标题中包含 key 的 PR
+	result := make([]PullRequest, 0, len(found))
+	for _, pr := range found {
+		if containsJiraKey(pr.Head, key) || containsJiraKey(pr.Title, key) {
+			result = append(result, pr)
+		}
+	}
+
+	return result, nil
+}
+
+// pick returns the single candidate or falls back to interactive selection
+func (c *Client) pick(resolved *ResolvedPR, opts ResolveOptions, what string, candidates []PullRequest) (*ResolvedPR, error) {
+	switch len(candidates) {
+	case 0:
+		return c.selectPR(resolved, opts, fmt.Sprintf("No PR found for %s", what), nil)
+	case 1:
+		resolved.PR = &candidates[0]
+		return resolved, nil
+	default:
+		return c.selectPR(resolved, opts, fmt.Sprintf("Multiple PRs found for %s", what), candidates)
+	}
+}
+
+// selectPR asks the caller to pick a PR, listing all open PRs when there are no candidates
+func (c *Client) selectPR(resolved *ResolvedPR, opts ResolveOptions, reason string, candidates []PullRequest) (*ResolvedPR, error) {
+	if opts.Select == nil {
+		if len(candidates) > 1 {
+			numbers := make([]string, len(candidates))
+			for i, pr := range candidates {
+				numbers[i] = fmt.Sprintf("#%d", pr.Number)
+			}
+			return nil, fmt.Errorf("%s: %s; specify the PR number", reason, strings.Join(numbers, ", "))
+		}
+		return nil, fmt.Errorf("%s: %w", reason, ErrPRNotFound)
+	}
+
+	if len(candidates) == 0 {
+		var err error
+		candidates, err = c.ListAllPullRequests(resolved.Owner, resolved.Repo, "open")
+		if err != nil {
+			return nil, err
+		}
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("%s and no open pull requests in %s/%s: %w", reason, resolved.Owner, resolved.Repo, ErrPRNotFound)
+		}
+	}
+
+	pr, err := opts.Select(reason, candidates)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, ErrPRSelectionCancelled
+	}
+
+	resolved.PR = pr
+	return resolved, nil
+}
+
+// containsJiraKey reports whether s contains key as a whole token, ignoring case
+func containsJiraKey(s, key string) bool {
+	for _, match := range jiraKeyTokenPattern.FindAllStringSubmatch(s, -1) {
+		if strings.EqualFold(match[1], key) {
+			return true
+		}
+	}
+	return false
+}
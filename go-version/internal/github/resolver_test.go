@@ -0,0 +1,143 @@
+package github
+
+import (
+	"testing"
+)
+
+func TestParsePRRef(t *testing.T) {
+	tests := []struct {
+		name       string
+		arg        string
+		wantKind   PRRefKind
+		wantOwner  string
+		wantRepo   string
+		wantNumber int
+		wantJira   string
+		wantBranch string
+		wantErr    bool
+	}{
+		{
+			name:     "Empty uses current branch",
+			arg:      "",
+			wantKind: PRRefCurrentBranch,
+		},
+		{
+			name:       "PR URL",
+			arg:        "https://github.com/brain/planning-api/pull/2001/files",
+			wantKind:   PRRefURL,
+			wantOwner:  "brain",
+			wantRepo:   "planning-api",
+			wantNumber: 2001,
+		},
+		{
+			name:       "Plain number",
+			arg:        "123",
+			wantKind:   PRRefNumber,
+			wantNumber: 123,
+		},
+		{
+			name:       "Number with hash",
+			arg:        "#123",
+			wantKind:   PRRefNumber,
+			wantNumber: 123,
+		},
+		{
+			name:       "owner/repo#number",
+			arg:        "brain/planning-api#42",
+			wantKind:   PRRefRepoNumber,
+			wantOwner:  "brain",
+			wantRepo:   "planning-api",
+			wantNumber: 42,
+		},
+		{
+			name:     "Jira key",
+			arg:      "PROJ-123",
+			wantKind: PRRefJiraKey,
+			wantJira: "PROJ-123",
+		},
+		{
+			name:       "Branch with Jira key",
+			arg:        "PROJ-123--fix-login",
+			wantKind:   PRRefBranch,
+			wantBranch: "PROJ-123--fix-login",
+		},
+		{
+			name:       "Branch with slash",
+			arg:        "feature/new-login",
+			wantKind:   PRRefBranch,
+			wantBranch: "feature/new-login",
+		},
+		{
+			name:    "Zero PR number",
+			arg:     "0",
+			wantErr: true,
+		},
+		{
+			name:    "Hash without number",
+			arg:     "#abc",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid branch characters",
+			arg:     "not a branch",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParsePRRef(tt.arg)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePRRef() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if ref.Kind != tt.wantKind {
+				t.Errorf("ParsePRRef() kind = %v, want %v", ref.Kind, tt.wantKind)
+			}
+			if ref.Owner != tt.wantOwner || ref.Repo != tt.wantRepo {
+				t.Errorf("ParsePRRef() repo = %s/%s, want %s/%s", ref.Owner, ref.Repo, tt.wantOwner, tt.wantRepo)
+			}
+			if ref.Number != tt.wantNumber {
+				t.Errorf("ParsePRRef() number = %v, want %v", ref.Number, tt.wantNumber)
+			}
+			if ref.JiraKey != tt.wantJira {
+				t.Errorf("ParsePRRef() jira = %v, want %v", ref.JiraKey, tt.wantJira)
+			}
+			if ref.Branch != tt.wantBranch {
+				t.Errorf("ParsePRRef() branch = %v, want %v", ref.Branch, tt.wantBranch)
+			}
+		})
+	}
+}
+
+func TestContainsJiraKey(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		key  string
+		want bool
+	}{
+		{name: "Branch prefix", s: "PROJ-12--fix-login", key: "PROJ-12", want: true},
+		{name: "Title prefix", s: "PROJ-12: Fix login", key: "PROJ-12", want: true},
+		{name: "Lowercase branch", s: "feature/proj-12-fix", key: "PROJ-12", want: true},
+		{name: "Longer number", s: "PROJ-123--fix", key: "PROJ-12", want: false},
+		{name: "Longer project", s: "XPROJ-12--fix", key: "PROJ-12", want: false},
+		{name: "Second key", s: "OTHER-1 PROJ-12: Fix login", key: "PROJ-12", want: true},
+		{name: "Key followed by letters", s: "proj-12fix", key: "PROJ-12", want: true},
+		{name: "Missing", s: "fix-login", key: "PROJ-12", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containsJiraKey(tt.s, tt.key); got != tt.want {
+				t.Errorf("containsJiraKey(%q, %q) = %v, want %v", tt.s, tt.key, got, tt.want)
+			}
+		})
+	}
+}
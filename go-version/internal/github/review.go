@@ -0,0 +1,338 @@
+package github
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// ReviewEvent is the action performed by a pull request review
+type ReviewEvent string
+
+const (
+	// ReviewEventApprove approves the pull request
+	ReviewEventApprove ReviewEvent = "APPROVE"
+	// ReviewEventRequestChanges requests changes on the pull request
+	ReviewEventRequestChanges ReviewEvent = "REQUEST_CHANGES"
+	// ReviewEventComment submits general feedback without approval
+	ReviewEventComment ReviewEvent = "COMMENT"
+)
+
+// ReviewComment is an inline comment attached to a review
+type ReviewComment struct {
+	Path      string
+	StartLine int // 多行评论的起始行，单行评论为 0
+	Line      int
+	Body      string
+}
+
+// CreateReviewInput contains the input for submitting a review
+type CreateReviewInput struct {
+	Event    ReviewEvent
+	Body     string
+	Comments []ReviewComment
+}
+
+// ReviewThread is a thread of inline review comments
+type ReviewThread struct {
+	ID         string
+	Path       string
+	Line       int
+	IsResolved bool
+	IsOutdated bool
+	Comments   []ThreadComment
+}
+
+// ThreadComment is a single comment in a review thread
+type ThreadComment struct {
+	ID     int64
+	Author string
+	Body   string
+}
+
+// CreateReview submits a review on a pull request
+func (c *Client) CreateReview(owner, repo string, number int, input CreateReviewInput) error {
+	event := string(input.Event)
+	review := &github.PullRequestReviewRequest{
+		Event: &event,
+	}
+
+	if input.Body != "" {
+		review.Body = github.String(input.Body)
+	}
+
+	for _, comment := range input.Comments {
+		draft := &github.DraftReviewComment{
+			Path: github.String(comment.Path),
+			Body: github.String(comment.Body),
+			Line: github.Int(comment.Line),
+			Side: github.String("RIGHT"),
+		}
+		if comment.StartLine > 0 && comment.StartLine < comment.Line {
+			draft.StartLine = github.Int(comment.StartLine)
+			draft.StartSide = github.String("RIGHT")
+		}
+		review.Comments = append(review.Comments, draft)
+	}
+
+	_, _, err := c.client.PullRequests.CreateReview(c.ctx, owner, repo, number, review)
+	if err != nil {
+		return fmt.Errorf("failed to submit review: %w", err)
+	}
+
+	return nil
+}
+
+// ReplyToReviewComment replies to an inline review comment
+func (c *Client) ReplyToReviewComment(owner, repo string, number int, commentID int64, body string) error {
+	_, _, err := c.client.PullRequests.CreateCommentInReplyTo(c.ctx, owner, repo, number, body, commentID)
+	if err != nil {
+		return fmt.Errorf("failed to reply to comment %d: %w", commentID, err)
+	}
+
+	return nil
+}
+
+// ListReviewThreads lists the review threads of a pull request
+func (c *Client) ListReviewThreads(owner, repo string, number int) ([]ReviewThread, error) {
+	// REST API 不提供 review thread 信息，需要使用 GraphQL
+	query := `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100) {
+        nodes {
+          id
+          path
+          line
+          isResolved
+          isOutdated
+          comments(first: 50) {
+            nodes { databaseId body author { login } }
+          }
+        }
+      }
+    }
+  }
+}`
+
+	var data struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes []struct {
+						ID         string `json:"id"`
+						Path       string `json:"path"`
+						Line       int    `json:"line"`
+						IsResolved bool   `json:"isResolved"`
+						IsOutdated bool   `json:"isOutdated"`
+						Comments   struct {
+							Nodes []struct {
+								DatabaseID int64  `json:"databaseId"`
+								Body       string `json:"body"`
+								Author     struct {
+									Login string `json:"login"`
+								} `json:"author"`
+							} `json:"nodes"`
+						} `json:"comments"`
+					} `json:"nodes"`
+				} `json:"reviewThreads"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	}
+
+	variables := map[string]interface{}{
+		"owner":  owner,
+		"repo":   repo,
+		"number": number,
+	}
+	if err := c.graphQL(query, variables, &data); err != nil {
+		return nil, fmt.Errorf("failed to list review threads: %w", err)
+	}
+
+	threads := make([]ReviewThread, 0)
+	for _, node := range data.Repository.PullRequest.ReviewThreads.Nodes {
+		thread := ReviewThread{
+			ID:         node.ID,
+			Path:       node.Path,
+			Line:       node.Line,
+			IsResolved: node.IsResolved,
+			IsOutdated: node.IsOutdated,
+		}
+		for _, comment := range node.Comments.Nodes {
+			thread.Comments = append(thread.Comments, ThreadComment{
+				ID:     comment.DatabaseID,
+				Author: comment.Author.Login,
+				Body:   comment.Body,
+			})
+		}
+		threads = append(threads, thread)
+	}
+
+	return threads, nil
+}
+
+// ResolveReviewThread marks a review thread as resolved
+func (c *Client) ResolveReviewThread(threadID string) error {
+	query := `mutation($id: ID!) {
+  resolveReviewThread(input: {threadId: $id}) { thread { id } }
+}`
+
+	if err := c.graphQL(query, map[string]interface{}{"id": threadID}, nil); err != nil {
+		return fmt.Errorf("failed to resolve thread %s: %w", threadID, err)
+	}
+
+	return nil
+}
+
+// graphQL runs a GraphQL query and decodes its data into result
+func (c *Client) graphQL(query string, variables map[string]interface{}, result interface{}) error {
+	body := map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	}
+
+	req, err := c.client.NewRequest("POST", "graphql", body)
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := c.client.Do(c.ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if len(resp.Errors) > 0 {
+		messages := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			messages[i] = e.Message
+		}
+		return fmt.Errorf("%s", strings.Join(messages, "; "))
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, result)
+}
+
+var reviewCommentPattern = regexp.MustCompile(`^(\S+?):(\d+)(?:-(\d+))?:\s*(.*)$`)
+
+// ParseReviewComments parses inline review comments, one per line:
+//
+//	path/to/file.go:42: message
+//	path/to/file.go:40-42: message spanning several lines
+//	path/to/file.go:42: suggestion: replacement code
+//
+// Indented lines continue the previous comment; the indentation of its first
+// continuation line is removed from all of them, so code keeps its relative
+// indentation. Blank lines between continuation lines are kept; other blank
+// lines and lines starting with '#' are ignored. A "suggestion:" message
+// becomes a GitHub suggestion block, code on the "suggestion:" line itself
+// starting at the same level as the continuation lines.
+func ParseReviewComments(r io.Reader) ([]ReviewComment, error) {
+	comments := make([]ReviewComment, 0)
+	var bodies []*strings.Builder
+	indent := "" // 当前评论续行的缩进
+	blanks := 0  // 还没有写入的空行，后面有续行时才保留
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" {
+			blanks++
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			blanks = 0
+			continue
+		}
+
+		// 缩进行：续写上一条评论
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(bodies) == 0 {
+				return nil, fmt.Errorf("line %d: continuation without a preceding comment", lineNo)
+			}
+			line = strings.TrimRight(line, " \t")
+			if indent == "" {
+				indent = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			}
+			bodies[len(bodies)-1].WriteString(strings.Repeat("\n", blanks) + "\n" + unindent(line, indent))
+			blanks = 0
+			continue
+		}
+
+		m := reviewCommentPattern.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("line %d: expected 'path:line: message', got %q", lineNo, line)
+		}
+
+		comment := ReviewComment{Path: m[1]}
+		start, _ := strconv.Atoi(m[2])
+		if m[3] != "" {
+			end, _ := strconv.Atoi(m[3])
+			if end < start {
+				return nil, fmt.Errorf("line %d: invalid line range %s-%s", lineNo, m[2], m[3])
+			}
+			comment.StartLine, comment.Line = start, end
+		} else {
+			comment.Line = start
+		}
+		if comment.Line <= 0 {
+			return nil, fmt.Errorf("line %d: line numbers start at 1", lineNo)
+		}
+
+		comments = append(comments, comment)
+		body := &strings.Builder{}
+		body.WriteString(m[4])
+		bodies = append(bodies, body)
+		indent, blanks = "", 0
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read review comments: %w", err)
+	}
+
+	for i := range comments {
+		comments[i].Body = formatSuggestion(strings.TrimSpace(bodies[i].String()))
+		if comments[i].Body == "" {
+			return nil, fmt.Errorf("empty comment for %s:%d", comments[i].Path, comments[i].Line)
+		}
+	}
+
+	return comments, nil
+}
+
+// formatSuggestion turns "suggestion: code" into a GitHub suggestion block
+func formatSuggestion(body string) string {
+	if !strings.HasPrefix(strings.ToLower(body), "suggestion:") {
+		return body
+	}
+
+	// 和续行一样去掉缩进
+	code := strings.TrimLeft(body[len("suggestion:"):], " \t")
+	code = strings.TrimPrefix(code, "\n")
+	return fmt.Sprintf("```suggestion\n%s\n```", code)
+}
+
+// unindent removes the continuation indentation; lines indented less than
+// the first continuation line lose all of their indentation
+func unindent(line, indent string) string {
+	if strings.HasPrefix(line, indent) {
+		return line[len(indent):]
+	}
+	return strings.TrimLeft(line, " \t")
+}
@@ -0,0 +1,71 @@
+package github
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseReviewComments(t *testing.T) {
+	input := `# comments are ignored
+internal/git/operations.go:42: Please handle the error here
+cmd/main.go:10-12: This block can be simplified
+  and moved to a helper
+
+pkg/config/config.go:7: suggestion: 	return nil
+	if err != nil {
+		return err
+	}
+pkg/config/config.go:20: suggestion:
+    for _, v := range values {
+        total += v
+
+        count++
+    }
+
+pkg/config/config.go:30: Trailing blank lines are dropped
+
+`
+
+	comments, err := ParseReviewComments(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseReviewComments() error = %v", err)
+	}
+
+	want := []ReviewComment{
+		{Path: "internal/git/operations.go", Line: 42, Body: "Please handle the error here"},
+		{Path: "cmd/main.go", StartLine: 10, Line: 12, Body: "This block can be simplified\nand moved to a helper"},
+		{Path: "pkg/config/config.go", Line: 7, Body: "```suggestion\nreturn nil\nif err != nil {\n\treturn err\n}\n```"},
+		{Path: "pkg/config/config.go", Line: 20, Body: "```suggestion\nfor _, v := range values {\n    total += v\n\n    count++\n}\n```"},
+		{Path: "pkg/config/config.go", Line: 30, Body: "Trailing blank lines are dropped"},
+	}
+
+	if len(comments) != len(want) {
+		t.Fatalf("ParseReviewComments() got %d comments, want %d", len(comments), len(want))
+	}
+	for i := range want {
+		if comments[i] != want[i] {
+			t.Errorf("ParseReviewComments()[%d] = %#v, want %#v", i, comments[i], want[i])
+		}
+	}
+}
+
+func TestParseReviewCommentsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Missing line number", input: "main.go: message"},
+		{name: "Continuation first", input: "  orphan line"},
+		{name: "Reversed range", input: "main.go:12-10: message"},
+		{name: "Zero line", input: "main.go:0: message"},
+		{name: "Empty message", input: "main.go:3:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseReviewComments(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("ParseReviewComments() expected error for %q", tt.input)
+			}
+		})
+	}
+}
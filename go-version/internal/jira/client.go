@@ -3,6 +3,7 @@ package jira
 import (
 	"fmt"
 	"io"
+	"regexp"
 	"strings"
 	"time"
 
@@ -46,6 +47,7 @@ type Issue struct {
 	Description string
 	Status      string
 	Type        string
+	FixVersions []string
 	Priority    string
 	Assignee    string
 	Reporter    string
@@ -88,9 +90,21 @@ func (c *Client) GetIssue(issueKey string) (*Issue, error) {
 		Description: issue.Fields.Description,
 		Status:      issue.Fields.Status.Name,
 		Type:        issue.Fields.Type.Name,
+		FixVersions: fixVersionNames(issue.Fields.FixVersions),
 	}, nil
 }
 
+// fixVersionNames returns the names of the issue's fix versions
+func fixVersionNames(versions []*jira.FixVersion) []string {
+	names := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if v != nil && v.Name != "" {
+			names = append(names, v.Name)
+		}
+	}
+	return names
+}
+
 // GetIssueDetailed gets a Jira issue with all details including attachments and comments
 func (c *Client) GetIssueDetailed(issueKey string) (*Issue, error) {
 	issue, _, err := c.client.Issue.Get(issueKey, nil)
@@ -317,6 +331,23 @@ func ExtractProjectKey(issueKey string) string {
 	return parts[0]
 }
 
+var issueKeyPattern = regexp.MustCompile(`[A-Z][A-Z0-9]+-\d+`)
+
+// ExtractIssueKeys extracts unique issue keys (e.g. "PROJ-123") from the given texts
+func ExtractIssueKeys(texts ...string) []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, text := range texts {
+		for _, key := range issueKeyPattern.FindAllString(text, -1) {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
 // ValidateIssueKey checks if an issue key is valid
 func ValidateIssueKey(issueKey string) bool {
 	if issueKey == "" {
@@ -19,6 +19,9 @@ type StatusMapping struct {
 	ProjectKey      string `json:"project_key"`
 	PRCreatedStatus string `json:"pr_created_status"`
 	PRMergedStatus  string `json:"pr_merged_status"`
+
+	// 合并的 PR 被 revert 后 ticket 回到的状态，首次 revert 时设置
+	PRRevertedStatus string `json:"pr_reverted_status,omitempty"`
 }
 
 // CacheData represents the entire cache file structure
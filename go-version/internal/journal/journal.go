@@ -0,0 +1,145 @@
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Step is a completed step of pr create
+type Step string
+
+// Steps of pr create, in the order they run
+const (
+	StepBranch   Step = "branch"
+	StepCommit   Step = "commit"
+	StepPush     Step = "push"
+	StepPR       Step = "pr"
+	StepStack    Step = "stack"
+	StepJira     Step = "jira"
+	StepWatching Step = "watching"
+)
+
+// Journal records the inputs and progress of a pr create run so that it can
+// be resumed with --continue or rolled back with --abort
+type Journal struct {
+	OriginalBranch string `json:"original_branch"`
+	StartSHA       string `json:"start_sha"`    // 开始前的 HEAD
+	SnapshotRef    string `json:"snapshot_ref"` // 开始前 HEAD、暂存区和工作区的快照
+	Branch         string `json:"branch"`
+	BaseBranch     string `json:"base_branch"`
+
+	// ExistingBranch 表示从已提交的分支创建 PR，分支和提交不是 pr create 创建的
+	ExistingBranch      bool   `json:"existing_branch,omitempty"`
+	BranchCreated       bool   `json:"branch_created,omitempty"` // 分支是 pr create 新建的，回滚时删除
+	RemoteBranchExisted bool   `json:"remote_branch_existed,omitempty"`
+	RebasedFrom         string `json:"rebased_from,omitempty"` // 推送前 rebase 到 base 分支之前的 HEAD
+
+	BaseOwner  string `json:"base_owner"`
+	BaseRepo   string `json:"base_repo"`
+	BaseRemote string `json:"base_remote"`
+	HeadOwner  string `json:"head_owner"`
+	HeadRepo   string `json:"head_repo"`
+	HeadRemote string `json:"head_remote"`
+
+	JiraTicket    string `json:"jira_ticket,omitempty"`
+	Title         string `json:"title,omitempty"` // PR 标题
+	CommitMessage string `json:"commit_message"`
+	Body          string `json:"body"`
+
+	// 提交时暂存的内容：只用已暂存的内容，或暂存指定路径，都为空时暂存全部
+	StagedOnly bool     `json:"staged_only,omitempty"`
+	StagePaths []string `json:"stage_paths,omitempty"`
+	NoVerify   bool     `json:"no_verify,omitempty"` // 提交时跳过 git 钩子
+	NoScan     bool     `json:"no_scan,omitempty"`   // 推送前不检查凭据和大文件
+	NoChecks   bool     `json:"no_checks,omitempty"` // 推送前不运行 .qkflow.yaml 中的检查
+
+	Interactive bool `json:"interactive,omitempty"` // 没有 --types 和 --pr-desc，可以提示用户
+
+	Sign         string `json:"sign,omitempty"`          // --sign 指定的签名方式
+	SignRequired bool   `json:"sign_required,omitempty"` // 分支保护要求签名提交
+
+	Stack          bool   `json:"stack,omitempty"`
+	StackParentSHA string `json:"stack_parent_sha,omitempty"`
+
+	PRNumber int    `json:"pr_number,omitempty"`
+	PRURL    string `json:"pr_url,omitempty"`
+
+	Steps     []Step `json:"steps"`
+	StartedAt string `json:"started_at"`
+
+	filePath string `json:"-"`
+}
+
+// New creates an empty journal stored at filePath
+func New(filePath string) *Journal {
+	return &Journal{
+		Steps:     make([]Step, 0),
+		StartedAt: time.Now().Format(time.RFC3339),
+		filePath:  filePath,
+	}
+}
+
+// Load reads the journal at filePath, returning nil if there is none
+func Load(filePath string) (*Journal, error) {
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	j := New(filePath)
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("failed to parse journal %s: %w", filePath, err)
+	}
+
+	return j, nil
+}
+
+// Save writes the journal to file
+func (j *Journal) Save() error {
+	if err := os.MkdirAll(filepath.Dir(j.filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal: %w", err)
+	}
+
+	if err := os.WriteFile(j.filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	return nil
+}
+
+// Remove deletes the journal file
+func (j *Journal) Remove() error {
+	if err := os.Remove(j.filePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove journal: %w", err)
+	}
+	return nil
+}
+
+// Done reports whether a step has completed
+func (j *Journal) Done(step Step) bool {
+	for _, s := range j.Steps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// Mark records a step as completed and saves the journal
+func (j *Journal) Mark(step Step) error {
+	if !j.Done(step) {
+		j.Steps = append(j.Steps, step)
+	}
+	return j.Save()
+}
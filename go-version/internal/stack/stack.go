@@ -0,0 +1,178 @@
+package stack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Wangggym/quick-workflow/internal/utils"
+)
+
+// Branch is a branch of a PR stack
+type Branch struct {
+	Name     string `json:"name"`
+	Parent   string `json:"parent"`
+	PRNumber int    `json:"pr_number"`
+	// ParentSHA 是分支最近一次基于的父分支提交，父分支被 squash 合并或重写后
+	// 用它作为 rebase --onto 的 upstream，只移植本分支自己的提交
+	ParentSHA string `json:"parent_sha"`
+}
+
+// Stack holds the stacked branches of a repository
+type Stack struct {
+	Owner    string   `json:"owner"`
+	Repo     string   `json:"repo"`
+	Branches []Branch `json:"branches"`
+	filePath string   `json:"-"`
+}
+
+// Load loads the stack of a repository, returning an empty stack if none is saved
+func Load(owner, repo string) (*Stack, error) {
+	configDir, err := utils.GetConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	dir := filepath.Join(configDir, "stacks")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create stacks directory: %w", err)
+	}
+
+	filePath := filepath.Join(dir, fmt.Sprintf("%s_%s.json", strings.ToLower(owner), strings.ToLower(repo)))
+
+	s := &Stack{
+		Owner:    owner,
+		Repo:     repo,
+		Branches: make([]Branch, 0),
+		filePath: filePath,
+	}
+
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stack: %w", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse stack: %w", err)
+	}
+
+	return s, nil
+}
+
+// Save saves the stack to file
+func (s *Stack) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal stack: %w", err)
+	}
+
+	if err := os.WriteFile(s.filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write stack: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the branch with the given name, or nil if it is not in the stack
+func (s *Stack) Get(name string) *Branch {
+	for i := range s.Branches {
+		if s.Branches[i].Name == name {
+			return &s.Branches[i]
+		}
+	}
+	return nil
+}
+
+// Set adds the branch or replaces the existing one with the same name
+func (s *Stack) Set(branch Branch) {
+	if existing := s.Get(branch.Name); existing != nil {
+		*existing = branch
+		return
+	}
+	s.Branches = append(s.Branches, branch)
+}
+
+// Remove removes a branch, moving its children onto its parent
+func (s *Stack) Remove(name string) {
+	removed := s.Get(name)
+	if removed == nil {
+		return
+	}
+	parent := removed.Parent
+
+	branches := make([]Branch, 0, len(s.Branches))
+	for _, b := range s.Branches {
+		if b.Name == name {
+			continue
+		}
+		if b.Parent == name {
+			b.Parent = parent
+		}
+		branches = append(branches, b)
+	}
+	s.Branches = branches
+}
+
+// Children returns the branches stacked directly on the given branch
+func (s *Stack) Children(name string) []Branch {
+	children := make([]Branch, 0)
+	for _, b := range s.Branches {
+		if b.Parent == name {
+			children = append(children, b)
+		}
+	}
+	return children
+}
+
+// Roots returns the branches whose parent is not part of the stack (e.g. main)
+func (s *Stack) Roots() []Branch {
+	roots := make([]Branch, 0)
+	for _, b := range s.Branches {
+		if s.Get(b.Parent) == nil {
+			roots = append(roots, b)
+		}
+	}
+	return roots
+}
+
+// Ordered returns all branches with every parent before its children
+func (s *Stack) Ordered() []Branch {
+	ordered := make([]Branch, 0, len(s.Branches))
+	visited := make(map[string]bool)
+
+	var walk func(b Branch)
+	walk = func(b Branch) {
+		if visited[b.Name] {
+			return
+		}
+		visited[b.Name] = true
+		ordered = append(ordered, b)
+		for _, child := range s.Children(b.Name) {
+			walk(child)
+		}
+	}
+
+	for _, root := range s.Roots() {
+		walk(root)
+	}
+
+	return ordered
+}
+
+// Trunk returns the branch at the bottom of the chain containing name
+func (s *Stack) Trunk(name string) string {
+	seen := make(map[string]bool)
+	for {
+		b := s.Get(name)
+		if b == nil || seen[name] {
+			return name
+		}
+		seen[name] = true
+		name = b.Parent
+	}
+}
@@ -0,0 +1,65 @@
+package stack
+
+import (
+	"reflect"
+	"testing"
+)
+
+func names(branches []Branch) []string {
+	result := make([]string, 0, len(branches))
+	for _, b := range branches {
+		result = append(result, b.Name)
+	}
+	return result
+}
+
+func newTestStack() *Stack {
+	return &Stack{
+		Branches: []Branch{
+			{Name: "c", Parent: "b"},
+			{Name: "a", Parent: "main"},
+			{Name: "b", Parent: "a"},
+			{Name: "x", Parent: "main"},
+			{Name: "b2", Parent: "a"},
+		},
+	}
+}
+
+func TestOrdered(t *testing.T) {
+	got := names(newTestStack().Ordered())
+	want := []string{"a", "b", "c", "b2", "x"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Ordered() = %v, want %v", got, want)
+	}
+}
+
+func TestRemoveReparentsChildren(t *testing.T) {
+	s := newTestStack()
+	s.Remove("a")
+
+	if s.Get("a") != nil {
+		t.Fatal("branch a should be removed")
+	}
+	for _, name := range []string{"b", "b2"} {
+		if parent := s.Get(name).Parent; parent != "main" {
+			t.Errorf("%s parent = %q, want main", name, parent)
+		}
+	}
+	if parent := s.Get("c").Parent; parent != "b" {
+		t.Errorf("c parent = %q, want b", parent)
+	}
+}
+
+func TestTrunk(t *testing.T) {
+	s := newTestStack()
+	tests := map[string]string{
+		"c":    "main",
+		"x":    "main",
+		"main": "main",
+	}
+	for name, want := range tests {
+		if got := s.Trunk(name); got != want {
+			t.Errorf("Trunk(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/fatih/color"
@@ -138,6 +139,23 @@ func PromptMultiSelect(message string, options []string) ([]string, error) {
 	return results, nil
 }
 
+// PromptMultiSelectDefaults prompts for selecting multiple options with some pre-selected
+func PromptMultiSelectDefaults(message string, options, defaults []string) ([]string, error) {
+	prompt := &survey.MultiSelect{
+		Message:  message,
+		Options:  options,
+		Default:  defaults,
+		PageSize: 15,
+	}
+
+	var results []string
+	if err := survey.AskOne(prompt, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
 // PRTypeOptions returns the standard PR type options
 func PRTypeOptions() []string {
 	return []string{
@@ -153,22 +171,15 @@ func PRTypeOptions() []string {
 	}
 }
 
-// ExtractPRType extracts the PR type from the selected option
+// ExtractPRType extracts the PR type from the selected option, e.g. "feat"
+// from "✨ feat: New feature"; an option without ':' is returned trimmed
 func ExtractPRType(option string) string {
-	// 移除 emoji 和描述，只保留类型
-	if len(option) < 3 {
-		return option
+	// 去掉 emoji 和描述，只保留冒号前的最后一个词
+	before, _, _ := strings.Cut(option, ":")
+	fields := strings.Fields(before)
+	if len(fields) == 0 {
+		return strings.TrimSpace(option)
 	}
-	
-	// 找到第一个冒号
-	for i, r := range option {
-		if r == ':' {
-			if i > 0 {
-				return option[3:i] // 跳过 emoji
-			}
-		}
-	}
-	
-	return option
+	return fields[len(fields)-1]
 }
 
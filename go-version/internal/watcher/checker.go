@@ -76,7 +76,7 @@ func (c *Checker) CheckMergedPRs(watchingList *WatchingList, state *State) ([]Me
 			Number:      watchingPR.PRNumber,
 			Title:       pr.Title,
 			URL:         pr.HTMLURL,
-			Branch:      pr.Head,
+			Branch:      pr.HeadLabel(watchingPR.Owner, watchingPR.Repo),
 			MergedAt:    pr.MergedAt,
 			MergedBy:    pr.MergedBy,
 			JiraTickets: jiraTickets,
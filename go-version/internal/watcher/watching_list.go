@@ -16,6 +16,7 @@ type WatchingPR struct {
 	Owner       string   `json:"owner"`
 	Repo        string   `json:"repo"`
 	Branch      string   `json:"branch"`
+	HeadOwner   string   `json:"head_owner,omitempty"` // fork 的所有者，同仓库 PR 为空
 	Title       string   `json:"title"`
 	PRURL       string   `json:"pr_url"`
 	JiraTickets []string `json:"jira_tickets"`
@@ -130,6 +131,16 @@ func (w *WatchingList) Remove(owner, repo string, prNumber int) error {
 	return w.Save()
 }
 
+// Get returns the watching entry of a PR, or nil if it is not watched
+func (w *WatchingList) Get(owner, repo string, prNumber int) *WatchingPR {
+	for i, pr := range w.PRs {
+		if pr.Owner == owner && pr.Repo == repo && pr.PRNumber == prNumber {
+			return &w.PRs[i]
+		}
+	}
+	return nil
+}
+
 // GetAll returns all watching PRs
 func (w *WatchingList) GetAll() []WatchingPR {
 	return w.PRs
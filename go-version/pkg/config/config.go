@@ -25,6 +25,31 @@ type Config struct {
 	CerebrasURL        string `mapstructure:"cerebras_url"`
 	AIProvider         string `mapstructure:"ai_provider"` // "auto", "deepseek", "openai", "cerebras"
 	AutoUpdate         bool   `mapstructure:"auto_update"`
+	PreCommitCheck     bool   `mapstructure:"pre_commit_check"` // pr create 创建分支前先运行 pre-commit 钩子
+	CommitSigning      string `mapstructure:"commit_signing"`   // "gpg" 或 "ssh"，为空时沿用 git 配置
+	SigningKey         string `mapstructure:"signing_key"`      // GPG key ID 或 SSH 公钥路径
+	SyncStrategy       string `mapstructure:"sync_strategy"`    // qkflow sync 使用 "rebase"（默认）或 "merge"
+
+	// PR 目标分支选择
+	BaseBranchPatterns []string         `mapstructure:"base_branch_patterns"` // e.g. ["main", "release/*"]
+	BaseBranchRules    []BaseBranchRule `mapstructure:"base_branch_rules"`
+
+	// 分支命名，仓库中的 .qkflow.yaml 可以覆盖
+	BranchNaming BranchNaming `mapstructure:"branch_naming"`
+
+	// 提交信息，仓库中的 .qkflow.yaml 可以覆盖
+	CommitMessage CommitConvention `mapstructure:"commit_message"`
+
+	// 推送前检查凭据和大文件，仓库中的 .qkflow.yaml 可以覆盖
+	PushScan PushScan `mapstructure:"push_scan"`
+}
+
+// BaseBranchRule picks the PR base branch from the Jira ticket type.
+// Branch may contain {fix_version}, replaced by the ticket's fix version,
+// e.g. {issue_type: Bug, branch: "release/{fix_version}"}.
+type BaseBranchRule struct {
+	IssueType string `mapstructure:"issue_type" yaml:"issue_type"`
+	Branch    string `mapstructure:"branch" yaml:"branch"`
 }
 
 var globalConfig *Config
@@ -123,6 +148,15 @@ func Save(cfg *Config) error {
 	viper.Set("cerebras_url", cfg.CerebrasURL)
 	viper.Set("ai_provider", cfg.AIProvider)
 	viper.Set("auto_update", cfg.AutoUpdate)
+	viper.Set("pre_commit_check", cfg.PreCommitCheck)
+	viper.Set("commit_signing", cfg.CommitSigning)
+	viper.Set("signing_key", cfg.SigningKey)
+	viper.Set("sync_strategy", cfg.SyncStrategy)
+	viper.Set("base_branch_patterns", cfg.BaseBranchPatterns)
+	viper.Set("base_branch_rules", cfg.BaseBranchRules)
+	viper.Set("branch_naming", cfg.BranchNaming)
+	viper.Set("commit_message", cfg.CommitMessage)
+	viper.Set("push_scan", cfg.PushScan)
 
 	// 写入文件
 	if err := viper.WriteConfigAs(configFile); err != nil {
@@ -163,6 +197,7 @@ func setDefaults() {
 	viper.SetDefault("branch_prefix", "")
 	viper.SetDefault("auto_update", true)                                                  // 默认启用自动更新
 	viper.SetDefault("ai_provider", "auto")                                                // 默认自动选择 AI provider
+	viper.SetDefault("sync_strategy", "rebase")                                            // qkflow sync 默认 rebase
 	viper.SetDefault("cerebras_url", "https://cerebras-proxy.brain.loocaa.com:1443/v1")    // 默认 Cerebras URL
 }
 
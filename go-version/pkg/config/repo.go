@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+// RepoConfigFile is the per-repository config file, kept at the root of the
+// working tree and shared with everyone working on the repository
+const RepoConfigFile = ".qkflow.yaml"
+
+// RepoConfig holds the settings of a repository
+type RepoConfig struct {
+	Branch BranchNaming     `mapstructure:"branch"`
+	Commit CommitConvention `mapstructure:"commit"`
+	Scan   PushScan         `mapstructure:"scan"`
+	Checks []Check          `mapstructure:"checks"`
+}
+
+// BranchNaming controls the names of the branches created by pr create
+type BranchNaming struct {
+	// Template 是 Go 模板，可用字段: .Ticket .Type .Slug .User .Date，
+	// 例如 "feature/{{.Ticket}}-{{.Slug}}"
+	Template      string `mapstructure:"template" yaml:"template,omitempty"`
+	SlugMaxLength int    `mapstructure:"slug_max_length" yaml:"slug_max_length,omitempty"`
+	Lowercase     bool   `mapstructure:"lowercase" yaml:"lowercase,omitempty"` // slug 转为小写
+	Pattern       string `mapstructure:"pattern" yaml:"pattern,omitempty"`     // 分支名必须匹配的正则
+}
+
+// CommitConvention controls the commit messages created by pr create
+type CommitConvention struct {
+	// Template 是 Go 模板，生成提交信息的第一行，可用字段: .Ticket .Type .Scope .Title .Summary
+	Template     string   `mapstructure:"template" yaml:"template,omitempty"`
+	Conventional bool     `mapstructure:"conventional" yaml:"conventional,omitempty"` // 按 Conventional Commits 校验
+	Types        []string `mapstructure:"types" yaml:"types,omitempty"`               // 允许的类型
+	Scopes       []string `mapstructure:"scopes" yaml:"scopes,omitempty"`             // 允许的 scope
+	JiraLink     bool     `mapstructure:"jira_link" yaml:"jira_link,omitempty"`       // 正文中附上 Jira 链接
+	CoAuthors    []string `mapstructure:"co_authors" yaml:"co_authors,omitempty"`     // "Name <email>"
+}
+
+// PushScan controls the scan for credentials and unwanted files before pushing
+type PushScan struct {
+	Mode          string   `mapstructure:"mode" yaml:"mode,omitempty"`                         // "block"（默认）、"warn" 或 "off"
+	MaxFileSizeMB int      `mapstructure:"max_file_size_mb" yaml:"max_file_size_mb,omitempty"` // 超过该大小的文件被报告，默认 5
+	AllowPaths    []string `mapstructure:"allow_paths" yaml:"allow_paths,omitempty"`           // 不检查的文件: glob 或目录 "testdata/"
+	AllowPatterns []string `mapstructure:"allow_patterns" yaml:"allow_patterns,omitempty"`     // 匹配这些正则的内容不报告
+}
+
+// Check is a command run on the changed files before pushing, e.g. a linter
+type Check struct {
+	Name  string   `mapstructure:"name" yaml:"name"`
+	Run   string   `mapstructure:"run" yaml:"run"`               // shell 命令，{files} 替换为匹配的改动文件
+	Paths []string `mapstructure:"paths" yaml:"paths,omitempty"` // 只在匹配的文件改动时运行，为空时总是运行
+}
+
+// LoadRepo reads the config of the repository rooted at dir. A missing file
+// gives an empty config.
+func LoadRepo(dir string) (*RepoConfig, error) {
+	file := filepath.Join(dir, RepoConfigFile)
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return &RepoConfig{}, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(file)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", RepoConfigFile, err)
+	}
+
+	var repo RepoConfig
+	if err := v.Unmarshal(&repo); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", RepoConfigFile, err)
+	}
+
+	return &repo, nil
+}
+
+// BranchNamingFor merges the branch naming of the repository over the global config
+func BranchNamingFor(repo *RepoConfig) BranchNaming {
+	var naming BranchNaming
+	if cfg := Get(); cfg != nil {
+		naming = cfg.BranchNaming
+	}
+	if repo == nil {
+		return naming
+	}
+
+	r := repo.Branch
+	if r.Template != "" {
+		naming.Template = r.Template
+	}
+	if r.SlugMaxLength > 0 {
+		naming.SlugMaxLength = r.SlugMaxLength
+	}
+	if r.Lowercase {
+		naming.Lowercase = true
+	}
+	if r.Pattern != "" {
+		naming.Pattern = r.Pattern
+	}
+	return naming
+}
+
+// CommitConventionFor merges the commit convention of the repository over the global config
+func CommitConventionFor(repo *RepoConfig) CommitConvention {
+	var convention CommitConvention
+	if cfg := Get(); cfg != nil {
+		convention = cfg.CommitMessage
+	}
+	if repo == nil {
+		return convention
+	}
+
+	r := repo.Commit
+	if r.Template != "" {
+		convention.Template = r.Template
+	}
+	if r.Conventional {
+		convention.Conventional = true
+	}
+	if len(r.Types) > 0 {
+		convention.Types = r.Types
+	}
+	if len(r.Scopes) > 0 {
+		convention.Scopes = r.Scopes
+	}
+	if r.JiraLink {
+		convention.JiraLink = true
+	}
+	// 共同作者来自两处时合并
+	convention.CoAuthors = append(convention.CoAuthors, r.CoAuthors...)
+	return convention
+}
+
+// PushScanFor merges the push scan settings of the repository over the global
+// config. The repository can make the mode stricter but not weaker.
+func PushScanFor(repo *RepoConfig) PushScan {
+	var scan PushScan
+	if cfg := Get(); cfg != nil {
+		scan = cfg.PushScan
+	}
+	if repo == nil {
+		return scan
+	}
+
+	r := repo.Scan
+	if r.Mode != "" && scanModeLevel(r.Mode) > scanModeLevel(scan.Mode) {
+		scan.Mode = r.Mode
+	}
+	if r.MaxFileSizeMB > 0 {
+		scan.MaxFileSizeMB = r.MaxFileSizeMB
+	}
+	// 允许列表来自两处时合并
+	scan.AllowPaths = append(scan.AllowPaths, r.AllowPaths...)
+	scan.AllowPatterns = append(scan.AllowPatterns, r.AllowPatterns...)
+	return scan
+}
+
+// scanModeLevel orders the scan modes from the weakest to the strictest.
+// Unknown modes come last so that they are reported as invalid.
+func scanModeLevel(mode string) int {
+	switch mode {
+	case "off":
+		return 0
+	case "warn":
+		return 1
+	case "", "block":
+		return 2
+	}
+	return 3
+}
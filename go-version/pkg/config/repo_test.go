@@ -0,0 +1,29 @@
+package config
+
+import "testing"
+
+func TestPushScanForMode(t *testing.T) {
+	saved := globalConfig
+	t.Cleanup(func() { globalConfig = saved })
+
+	tests := []struct {
+		global, repo, want string
+	}{
+		{global: "", repo: "", want: ""},
+		{global: "", repo: "off", want: ""},
+		{global: "block", repo: "warn", want: "block"},
+		{global: "warn", repo: "off", want: "warn"},
+		{global: "warn", repo: "block", want: "block"},
+		{global: "off", repo: "warn", want: "warn"},
+		{global: "off", repo: "", want: "off"},
+		{global: "warn", repo: "strict", want: "strict"},
+	}
+
+	for _, tt := range tests {
+		globalConfig = &Config{PushScan: PushScan{Mode: tt.global}}
+		got := PushScanFor(&RepoConfig{Scan: PushScan{Mode: tt.repo}})
+		if got.Mode != tt.want {
+			t.Errorf("PushScanFor() with global %q and repo %q = %q, want %q", tt.global, tt.repo, got.Mode, tt.want)
+		}
+	}
+}